@@ -0,0 +1,211 @@
+package sego
+
+import (
+	"bufio"
+	_ "embed"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 内置的二元语法模型，见SegmentBigram
+//
+//go:embed data/bigram.txt
+var builtinBigrams string
+
+var (
+	defaultBigramOnce  sync.Once
+	defaultBigramModel *BigramModel
+)
+
+// 二元语法模型，记录相邻两个词在语料中一起出现的次数
+type BigramModel struct {
+	counts map[string]map[string]int // counts[w1][w2]为w1后面紧跟w2的次数
+	totals map[string]int            // totals[w1]为w1后面跟任何词的总次数
+}
+
+// 从文件中载入二元语法模型，见ParseBigramModel
+func LoadBigramModel(path string) (*BigramModel, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ParseBigramModel(file)
+}
+
+// 解析二元语法模型
+//
+// 每行为"前一个词 后一个词 次数"，英文词按小写处理。空行、#开头的注释行以及
+// 格式错误或次数不是正整数的行被忽略，同一词对出现多次时次数相加。
+func ParseBigramModel(reader io.Reader) (*BigramModel, error) {
+	model := &BigramModel{counts: make(map[string]map[string]int), totals: make(map[string]int)}
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		count, err := strconv.Atoi(fields[2])
+		if err != nil || count <= 0 {
+			continue
+		}
+		w1, w2 := strings.ToLower(fields[0]), strings.ToLower(fields[1])
+		row, ok := model.counts[w1]
+		if !ok {
+			row = make(map[string]int)
+			model.counts[w1] = row
+		}
+		row[w2] += count
+		model.totals[w1] += count
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// 返回w1后面紧跟w2的次数
+func (model *BigramModel) Count(w1, w2 string) int {
+	return model.counts[w1][w2]
+}
+
+// 返回内置的二元语法模型
+//
+// 模型由data/dictionary.txt中多字词条内部相邻的子词统计得到（见data/bigram.txt
+// 开头的注释），反映的是通用词汇中常见的词语搭配。
+func DefaultBigramModel() *BigramModel {
+	defaultBigramOnce.Do(func() {
+		defaultBigramModel, _ = ParseBigramModel(strings.NewReader(builtinBigrams))
+	})
+	return defaultBigramModel
+}
+
+// 设置SegmentBigram使用的二元语法模型，为nil时使用内置模型
+//
+// 该函数不能和分词并发调用。
+func (dict *Dictionary) SetBigramModel(model *BigramModel) {
+	dict.bigram = model
+}
+
+// 用二元语法模型对文本分词
+//
+// Segment只考虑每个分词自身的词频，这里在分词路径上再加上相邻两个分词的转移
+// 概率：后一个分词w2的路径长度从-log2(P(w2))变为
+//
+//	-log2(P(w2)) - log2(1 + P(w2|w1)/P(w2))
+//
+// 其中P(w2|w1)由模型中的次数估计。模型中没有的词对和Segment相同，模型中经常
+// 一起出现的词对更容易被选中。模型见Dictionary.SetBigramModel，没有设置时使用
+// 内置模型。路径长度相等时和Segment一样按preferToken选择，结果是确定的。
+//
+// 该函数不使用句子缓存，可以和其他分词并发调用。
+func (seg *Segmenter) SegmentBigram(bytes []byte) []Segment {
+	bigram := *seg
+	bigram.cache = nil
+	if seg.dict != nil {
+		bigram.bigram = seg.dict.bigram
+	}
+	if bigram.bigram == nil {
+		bigram.bigram = DefaultBigramModel()
+	}
+	return bigram.internalSegment(bytes, false)
+}
+
+// 返回在prev之后选择token时路径长度的调整值，模型中没有该词对时为零
+func (model *BigramModel) transitionDelta(prev, token *Token) float32 {
+	row := model.counts[prev.Text()]
+	if row == nil {
+		return 0
+	}
+	count := row[token.Text()]
+	if count == 0 {
+		return 0
+	}
+	conditional := float64(count) / float64(model.totals[prev.Text()])
+	unigram := math.Exp2(-float64(token.distance))
+	return -float32(math.Log2(1 + conditional/unigram))
+}
+
+// 二元语法分词中以某个分词结束于某个字元的最短路径
+type bigramState struct {
+	token    *Token
+	distance float32
+	prev     int // 前一个分词在其结束字元处的状态下标，第一个分词为-1
+}
+
+// 用二元语法模型计算最短路径，返回和computeJumpers同样格式的跳转信息
+//
+// 和computeJumpers每个字元只保留一条最短路径不同，这里对结束于每个字元的每个
+// 候选分词都保留一条最短路径，因为之后的转移概率依赖于前一个分词。
+func (seg *Segmenter) computeBigramJumpers(text []Text) []jumper {
+	if len(text) == 0 {
+		return nil
+	}
+	maxTokenLength := 0
+	if seg.dict != nil {
+		maxTokenLength = seg.dict.maxTokenLength
+	}
+	if seg.maxTokenLength > 0 {
+		maxTokenLength = minInt(maxTokenLength, seg.maxTokenLength)
+	}
+
+	states := make([][]bigramState, len(text))
+	tokens := make([]*Token, maxTokenLength+1)
+	for current := 0; current < len(text); current++ {
+		numTokens := 0
+		if maxTokenLength > 0 {
+			numTokens = seg.dict.lookupTokens(
+				text[current:minInt(current+maxTokenLength, len(text))], tokens)
+		}
+		if numTokens == 0 || len(tokens[0].text) > 1 {
+			kind := pseudoKind(text[current])
+			tokens[numTokens] = &Token{text: []Text{text[current]}, frequency: 1, distance: 32,
+				pos: pseudoPOS(text[current], kind, seg.unknownPOS), kind: kind}
+			numTokens++
+		}
+
+		for iToken := 0; iToken < numTokens; iToken++ {
+			token := tokens[iToken]
+			location := current + len(token.text) - 1
+			state := bigramState{token: token, prev: -1}
+			base := token.distance + seg.overrideDelta(token)
+			if current == 0 {
+				state.distance = base
+			} else {
+				// 长度相等时按preferToken选择前一个分词，结果不依赖于候选的顺序
+				candidates := states[current-1]
+				for i, prev := range candidates {
+					distance := prev.distance + base + seg.bigram.transitionDelta(prev.token, token)
+					if state.prev < 0 || distance < state.distance ||
+						(distance == state.distance && preferToken(prev.token, candidates[state.prev].token)) {
+						state.distance = distance
+						state.prev = i
+					}
+				}
+			}
+			states[location] = append(states[location], state)
+		}
+	}
+
+	// 从最后一个字元处最短的路径向前回溯
+	jumpers := make([]jumper, len(text))
+	best := -1
+	last := states[len(text)-1]
+	for i := range last {
+		if best < 0 || last[i].distance < last[best].distance ||
+			(last[i].distance == last[best].distance && preferToken(last[i].token, last[best].token)) {
+			best = i
+		}
+	}
+	for location := len(text) - 1; location >= 0; {
+		state := states[location][best]
+		jumpers[location] = jumper{minDistance: state.distance, token: state.token}
+		best = state.prev
+		location -= len(state.token.text)
+	}
+	return jumpers
+}
@@ -0,0 +1,55 @@
+package sego
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSegmentBigram(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("研究 10 vn\n研究生 40 n\n生命 10 n\n命 10 n\n起源 10 n\n的 10 uj\n")
+	text := []byte("研究生命的起源")
+	expect(t, "研究生/n 命/n 的/uj 起源/n ", SegmentsToString(seg.Segment(text), false))
+
+	model, err := ParseBigramModel(strings.NewReader("# 注释\n研究 生命 50\n研究 方法 50\n错误的行\n生命 的 0\n"))
+	expect(t, "<nil>", err)
+	expect(t, "50", model.Count("研究", "生命"))
+	expect(t, "0", model.Count("生命", "的"))
+
+	seg.Dictionary().SetBigramModel(model)
+	expect(t, "研究/vn 生命/n 的/uj 起源/n ", SegmentsToString(seg.SegmentBigram(text), false))
+	// 不影响普通分词
+	expect(t, "研究生/n 命/n 的/uj 起源/n ", SegmentsToString(seg.Segment(text), false))
+
+	// 模型中没有的词对和Segment相同，位置正确
+	segments := seg.SegmentBigram([]byte("GitHub研究生起源"))
+	expect(t, "github/x 研究生/n 起源/n ", SegmentsToString(segments, false))
+	expect(t, "6", segments[1].Start())
+	expect(t, "15", segments[1].End())
+	expect(t, "0", len(seg.SegmentBigram(nil)))
+}
+
+func TestSegmentBigramTie(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("甲 8 n\n乙 8 n\n甲乙 2 n\n丙 14 n\n")
+	model, _ := ParseBigramModel(strings.NewReader("丁 戊 5\n"))
+	seg.Dictionary().SetBigramModel(model)
+	// 总词频为32，“甲乙”和“甲 乙”的路径长度都是4，“丙”的前一个分词按preferToken
+	// 选择字典序较小的“乙”
+	for i := 0; i < 10; i++ {
+		expect(t, "甲/n 乙/n 丙/n ", SegmentsToString(seg.SegmentBigram([]byte("甲乙丙")), false))
+	}
+}
+
+func TestDefaultBigramModel(t *testing.T) {
+	model := DefaultBigramModel()
+	expect(t, "true", model.Count("人民", "政府") > 0)
+	expect(t, "true", DefaultBigramModel() == model)
+
+	var seg Segmenter
+	seg.LoadDictionary("人 20 n\n民政 20 n\n府 20 n\n人民 6 n\n政府 6 n\n")
+	text := []byte("人民政府")
+	expect(t, "人/n 民政/n 府/n ", SegmentsToString(seg.Segment(text), false))
+	// 未设置模型时使用内置模型
+	expect(t, "人民/n 政府/n ", SegmentsToString(seg.SegmentBigram(text), false))
+}
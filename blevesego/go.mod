@@ -0,0 +1,21 @@
+module github.com/Aopro7/sego/blevesego
+
+go 1.19
+
+replace github.com/Aopro7/sego => ../
+
+require (
+	github.com/Aopro7/sego v0.0.0-00010101000000-000000000000
+	github.com/blevesearch/bleve/v2 v2.3.10
+)
+
+require (
+	github.com/adamzy/cedar-go v0.0.0-20170805034717-80a9c64b256d // indirect
+	github.com/blevesearch/bleve_index_api v1.0.6 // indirect
+	github.com/blevesearch/geo v0.1.18 // indirect
+	github.com/blevesearch/upsidedown_store_api v1.0.2 // indirect
+	github.com/golang/geo v0.0.0-20210211234256-740aa86cb551 // indirect
+	github.com/json-iterator/go v0.0.0-20171115153421-f7279a603ede // indirect
+	golang.org/x/text v0.8.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
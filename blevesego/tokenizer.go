@@ -0,0 +1,94 @@
+// 将sego分词器包装为bleve全文检索引擎的分词器（analysis.Tokenizer）
+//
+// 本包是一个独立的Go模块，只有使用bleve的程序才需要引入，sego本身不依赖bleve。
+// 用法：
+//
+//	var segmenter sego.Segmenter
+//	segmenter.LoadDictionary("dictionary.txt")
+//	blevesego.RegisterTokenizer("sego", &segmenter)
+//
+// 然后在bleve的自定义分析器中使用名为"sego"的分词器。
+package blevesego
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Aopro7/sego"
+	"github.com/blevesearch/bleve/v2/analysis"
+	"github.com/blevesearch/bleve/v2/registry"
+)
+
+// 实现bleve的analysis.Tokenizer接口的sego分词器
+type SegoTokenizer struct {
+	segmenter *sego.Segmenter
+}
+
+// 使用分词器seg创建bleve分词器，seg应已载入词典
+func NewSegoTokenizer(seg *sego.Segmenter) *SegoTokenizer {
+	return &SegoTokenizer{segmenter: seg}
+}
+
+// 对input分词，返回bleve的分词流
+//
+// 分词的Term是原文本中的字节（保留大小写，需要时用bleve的lowercase过滤器处理），
+// Start和End是字节位置，Position从1开始。空白和标点组成的分词被跳过，
+// 不占用Position。
+func (t *SegoTokenizer) Tokenize(input []byte) analysis.TokenStream {
+	segments := t.segmenter.Segment(input)
+	stream := make(analysis.TokenStream, 0, len(segments))
+	position := 1
+	for i := range segments {
+		term := segments[i].Bytes(input)
+		if len(term) == 0 || isSeparator(term) {
+			continue
+		}
+		stream = append(stream, &analysis.Token{
+			Start:    segments[i].Start(),
+			End:      segments[i].End(),
+			Term:     term,
+			Position: position,
+			Type:     tokenType(segments[i].Token().Kind(), term),
+		})
+		position++
+	}
+	return stream
+}
+
+// 在bleve中注册名为name的分词器，该分词器使用seg分词
+//
+// 和bleve的registry.RegisterTokenizer一样，name重复注册时panic。
+func RegisterTokenizer(name string, seg *sego.Segmenter) {
+	registry.RegisterTokenizer(name,
+		func(config map[string]interface{}, cache *registry.Cache) (analysis.Tokenizer, error) {
+			return NewSegoTokenizer(seg), nil
+		})
+}
+
+// 判断分词是否全部由空白和标点组成
+func isSeparator(term []byte) bool {
+	for current := 0; current < len(term); {
+		r, size := utf8.DecodeRune(term[current:])
+		if !unicode.IsSpace(r) && !unicode.IsPunct(r) {
+			return false
+		}
+		current += size
+	}
+	return true
+}
+
+// 返回分词对应的bleve分词类型：数字为Numeric，包含中日韩文字的为Ideographic，
+// 其余为AlphaNumeric
+func tokenType(kind sego.Kind, term []byte) analysis.TokenType {
+	if kind == sego.KindNumber {
+		return analysis.Numeric
+	}
+	for current := 0; current < len(term); {
+		r, size := utf8.DecodeRune(term[current:])
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			return analysis.Ideographic
+		}
+		current += size
+	}
+	return analysis.AlphaNumeric
+}
@@ -0,0 +1,44 @@
+package blevesego
+
+import (
+	"testing"
+
+	"github.com/Aopro7/sego"
+	"github.com/blevesearch/bleve/v2/analysis"
+	"github.com/blevesearch/bleve/v2/registry"
+)
+
+func TestTokenize(t *testing.T) {
+	var seg sego.Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\ngithub 10 nz\n")
+	stream := NewSegoTokenizer(&seg).Tokenize([]byte("中国人口，GitHub 2024"))
+
+	expected := []analysis.Token{
+		{Start: 0, End: 6, Term: []byte("中国"), Position: 1, Type: analysis.Ideographic},
+		{Start: 6, End: 12, Term: []byte("人口"), Position: 2, Type: analysis.Ideographic},
+		{Start: 15, End: 21, Term: []byte("GitHub"), Position: 3, Type: analysis.AlphaNumeric},
+		{Start: 22, End: 26, Term: []byte("2024"), Position: 4, Type: analysis.Numeric},
+	}
+	if len(stream) != len(expected) {
+		t.Fatalf("期待%d个分词，实际%v", len(expected), stream)
+	}
+	for i, token := range stream {
+		if token.String() != expected[i].String() {
+			t.Errorf("期待值=%s, 实际=%s", expected[i].String(), token.String())
+		}
+	}
+}
+
+func TestRegisterTokenizer(t *testing.T) {
+	var seg sego.Segmenter
+	seg.LoadDictionary("中国 10 ns\n")
+	RegisterTokenizer("sego_test", &seg)
+
+	tokenizer, err := registry.NewCache().TokenizerNamed("sego_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stream := tokenizer.Tokenize([]byte("中国")); len(stream) != 1 || string(stream[0].Term) != "中国" {
+		t.Errorf("分词结果错误：%v", stream)
+	}
+}
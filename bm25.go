@@ -0,0 +1,87 @@
+package sego
+
+import (
+	"math"
+)
+
+// BM25评分的参数
+type BM25Params struct {
+	// 词频饱和参数，越大词频对得分的影响越接近线性
+	K1 float64
+
+	// 文档长度归一化参数，0表示不考虑文档长度，1表示完全按长度归一化
+	B float64
+}
+
+// 返回常用的BM25参数，K1为1.5，B为0.75
+func DefaultBM25Params() BM25Params {
+	return BM25Params{K1: 1.5, B: 0.75}
+}
+
+// 用参数p计算BM25得分，见BM25Score
+func (p BM25Params) Score(query, docSegments []Segment, docCount int, avgDocLen float64, idfMap map[string]float64) float64 {
+	return BM25Score(query, docSegments, docCount, avgDocLen, idfMap, p.K1, p.B)
+}
+
+// 计算文档对查询的BM25得分
+//
+// 得分为查询中每个不同的词w的IDF(w)*tf*(k1+1)/(tf+k1*(1-b+b*dl/avgDocLen))之和，
+// 其中tf为w在文档中出现的次数，dl为文档的词数。词按分词文本比较，查询中
+// 重复的词只算一次；查询和文档中的空白和标点都不算作词（见isCollocationWord），
+// 也不计入dl。IDF取自idfMap（见BuildIDFMap），idfMap中没有的词视为在
+// docCount篇文档的语料库中没有出现过。avgDocLen不大于零时不按文档长度归一化。
+func BM25Score(query []Segment, docSegments []Segment, docCount int, avgDocLen float64, idfMap map[string]float64, k1, b float64) float64 {
+	docTerms, docLen := termFreq(docSegments)
+	lengthNorm := 1.0
+	if avgDocLen > 0 {
+		lengthNorm = 1 - b + b*float64(docLen)/avgDocLen
+	}
+
+	queryTerms, _ := termFreq(query)
+	score := 0.0
+	for word := range queryTerms {
+		tf := float64(docTerms[word])
+		if tf == 0 {
+			continue
+		}
+		idf, ok := idfMap[word]
+		if !ok {
+			idf = bm25IDF(docCount, 0)
+		}
+		score += idf * tf * (k1 + 1) / (tf + k1*lengthNorm)
+	}
+	return score
+}
+
+// 从语料库统计每个词的BM25 IDF值
+//
+// corpus中的每一项是一篇文档，文档可以由多段分词结果组成（比如每个句子或者
+// 每个字段的分词结果），一个词在一篇文档中出现多次只计一次。IDF使用和Lucene
+// 相同的非负形式log(1+(N-df+0.5)/(df+0.5))，其中N为文档数，df为包含该词的
+// 文档数。空白和标点不统计。
+func BuildIDFMap(corpus [][][]Segment) map[string]float64 {
+	docFreq := make(map[string]int)
+	for _, doc := range corpus {
+		seen := make(map[string]bool)
+		for _, segs := range doc {
+			for _, s := range segs {
+				word := s.token.Text()
+				if isCollocationWord(word) && !seen[word] {
+					seen[word] = true
+					docFreq[word]++
+				}
+			}
+		}
+	}
+
+	idfMap := make(map[string]float64, len(docFreq))
+	for word, df := range docFreq {
+		idfMap[word] = bm25IDF(len(corpus), df)
+	}
+	return idfMap
+}
+
+// 返回docCount篇文档中有df篇包含的词的BM25 IDF值
+func bm25IDF(docCount, df int) float64 {
+	return math.Log(1 + (float64(docCount)-float64(df)+0.5)/(float64(df)+0.5))
+}
@@ -0,0 +1,49 @@
+package sego
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBM25Score(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n经济 10 n\n发展 10 v\n日本 10 ns\n")
+	docs := [][]Segment{
+		seg.Segment([]byte("中国人口")),
+		seg.Segment([]byte("中国经济发展")),
+		seg.Segment([]byte("人口人口")),
+	}
+	corpus := make([][][]Segment, len(docs))
+	for i, doc := range docs {
+		corpus[i] = [][]Segment{doc}
+	}
+	idfMap := BuildIDFMap(corpus)
+	expect(t, "0.470", fmt.Sprintf("%.3f", idfMap["中国"]))
+	expect(t, "0.981", fmt.Sprintf("%.3f", idfMap["经济"]))
+
+	// 一篇文档中的多段分词结果合在一起统计，重复的词只计一次
+	expect(t, "0.693", fmt.Sprintf("%.3f", BuildIDFMap([][][]Segment{docs[:2], docs[2:]})["中国"]))
+
+	params := DefaultBM25Params()
+	expect(t, "{1.5 0.75}", params)
+	query := seg.Segment([]byte("人口经济"))
+	avgDocLen := 7.0 / 3
+	expect(t, "0.502", fmt.Sprintf("%.3f", params.Score(query, docs[0], len(docs), avgDocLen, idfMap)))
+	expect(t, "0.869", fmt.Sprintf("%.3f", params.Score(query, docs[1], len(docs), avgDocLen, idfMap)))
+	expect(t, "0.704", fmt.Sprintf("%.3f", params.Score(query, docs[2], len(docs), avgDocLen, idfMap)))
+
+	// b为0时不按文档长度归一化
+	expect(t, "0.671", fmt.Sprintf("%.3f", BM25Score(query, docs[2], len(docs), avgDocLen, idfMap, 1.5, 0)))
+
+	// 不在idfMap中的词按没有出现过计算IDF
+	expect(t, "2.079", fmt.Sprintf("%.3f", BM25Score(seg.Segment([]byte("日本")), seg.Segment([]byte("日本")), 3, 0, idfMap, 1.5, 0.75)))
+	expect(t, "0", BM25Score(query, nil, len(docs), avgDocLen, idfMap, 1.5, 0.75))
+
+	// 空白和标点不算作词，也不计入文档长度
+	punctuated := seg.Segment([]byte("中国， 人口。"))
+	expect(t, "0.502", fmt.Sprintf("%.3f", params.Score(seg.Segment([]byte("人口？经济")), punctuated, len(docs), avgDocLen, idfMap)))
+	idfMap = BuildIDFMap([][][]Segment{{punctuated}, {seg.Segment([]byte("经济！"))}})
+	expect(t, "3", len(idfMap))
+	_, ok := idfMap["，"]
+	expect(t, "false", ok)
+}
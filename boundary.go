@@ -0,0 +1,114 @@
+package sego
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// 字元边界策略，决定文本划分成字元时相邻的两个字符之间是否断开
+//
+// 默认策略（DefaultBoundaryPolicy）将连续的拉丁字母和数字合成一个字元，其他
+// 字符（包括中日韩文字和标点）各自成为一个字元。自定义策略可以用来处理商品
+// 型号之类的文本，比如"iPhone14ProMax手机壳"中字母和数字之间是否断开。
+type BoundaryPolicy interface {
+	// 返回true表示在prev和cur两个相邻字符之间断开
+	Break(prev, cur rune) bool
+}
+
+// 将普通函数作为BoundaryPolicy使用
+type BoundaryPolicyFunc func(prev, cur rune) bool
+
+// 调用f(prev, cur)
+func (f BoundaryPolicyFunc) Break(prev, cur rune) bool {
+	return f(prev, cur)
+}
+
+// 默认的字元边界策略，只在两个拉丁字母或数字（非中日韩文字）之间不断开
+type DefaultBoundaryPolicy struct{}
+
+// 见DefaultBoundaryPolicy
+func (DefaultBoundaryPolicy) Break(prev, cur rune) bool {
+	return !isAlphanumeric(prev) || !isAlphanumeric(cur)
+}
+
+// 判断字符是否为拉丁字母或数字（非中日韩文字），即UTF8编码不超过两个字节的
+// 字母或数字
+func isAlphanumeric(r rune) bool {
+	return utf8.RuneLen(r) <= 2 && (unicode.IsLetter(r) || unicode.IsNumber(r))
+}
+
+// 设置分词器划分字元时使用的边界策略
+//
+// 载入词典时词典中的分词也按同样的策略划分，因此需要在载入词典之前设置。
+func WithBoundaryPolicy(policy BoundaryPolicy) Option {
+	return func(seg *Segmenter) {
+		seg.boundaryPolicy = policy
+	}
+}
+
+// 设置分词器划分字元时是否合并连续的拉丁字母和数字，默认合并
+//
+// group为false时每个字符（包括拉丁字母和数字）各自成为一个字元，比如"abc123"
+// 划分为"a"、"b"、"c"、"1"、"2"、"3"六个字元，适用于按单字建立索引的场合。
+// 该选项优先于WithBoundaryPolicy，但设置了WithCharSplitter时不起作用。载入
+// 词典时词典中的分词也按同样的方式划分，因此需要在载入词典之前设置。
+func WithGroupAlphanumeric(group bool) Option {
+	return func(seg *Segmenter) {
+		seg.ungroupAlphanumeric = !group
+	}
+}
+
+// 在每两个字符之间都断开的边界策略，见WithGroupAlphanumeric
+var runeBoundaryPolicy = BoundaryPolicyFunc(func(prev, cur rune) bool {
+	return true
+})
+
+// 按分词器的字元划分器或边界策略将文本划分成字元
+func (seg *Segmenter) splitText(text Text) []Text {
+	words := seg.splitWords(text)
+	if seg.unicodeLowercase {
+		for i, word := range words {
+			words[i] = toLowerUnicode(word)
+		}
+	}
+	return words
+}
+
+// 按分词器的字元划分器或边界策略将文本划分成字元，只转换ASCII字母的大小写
+func (seg *Segmenter) splitWords(text Text) []Text {
+	if seg.charSplitter != nil {
+		return seg.charSplitter.Split(text)
+	}
+	if seg.ungroupAlphanumeric {
+		return splitTextToWordsWithPolicy(text, runeBoundaryPolicy)
+	}
+	if seg.boundaryPolicy == nil {
+		return splitTextToWords(text)
+	}
+	return splitTextToWordsWithPolicy(text, seg.boundaryPolicy)
+}
+
+// 按指定的边界策略将文本划分成字元
+//
+// 非法的UTF8字节无论策略如何都单独成为一个字元，和splitTextToWords一致。
+func splitTextToWordsWithPolicy(text Text, policy BoundaryPolicy) []Text {
+	output := make([]Text, 0, len(text)/3)
+	start := 0
+	var prev rune
+	prevInvalid := false
+	for current := 0; current < len(text); {
+		r, size := utf8.DecodeRune(text[current:])
+		invalid := r == utf8.RuneError && size == 1
+		if current != 0 && (invalid || prevInvalid || policy.Break(prev, r)) {
+			output = append(output, toLower(text[start:current]))
+			start = current
+		}
+		prev = r
+		prevInvalid = invalid
+		current += size
+	}
+	if len(text) != 0 {
+		output = append(output, toLower(text[start:]))
+	}
+	return output
+}
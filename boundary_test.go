@@ -0,0 +1,75 @@
+package sego
+
+import (
+	"testing"
+	"unicode"
+)
+
+func TestDefaultBoundaryPolicy(t *testing.T) {
+	for _, text := range []string{
+		"中国有十三亿人口",
+		"中国雅虎Yahoo! China致力于，领先的公益民生门户网站。",
+		"iPhone14ProMax手机壳",
+		"Je suis enchanté de cette pièce",
+		"A4纸\xe4b",
+		"",
+	} {
+		expect(t, bytesToString(splitTextToWords([]byte(text))),
+			bytesToString(splitTextToWordsWithPolicy([]byte(text), DefaultBoundaryPolicy{})))
+	}
+}
+
+func TestBoundaryPolicy(t *testing.T) {
+	// 字母和数字之间也断开
+	letterDigit := BoundaryPolicyFunc(func(prev, cur rune) bool {
+		return DefaultBoundaryPolicy{}.Break(prev, cur) ||
+			unicode.IsLetter(prev) != unicode.IsLetter(cur)
+	})
+	expect(t, "iphone/14/promax/手/机/壳/",
+		bytesToString(splitTextToWordsWithPolicy([]byte("iPhone14ProMax手机壳"), letterDigit)))
+	expect(t, "a/4/纸/", bytesToString(splitTextToWordsWithPolicy([]byte("A4纸"), letterDigit)))
+
+	// 字母数字后面紧跟的汉字不断开
+	skuUnit := BoundaryPolicyFunc(func(prev, cur rune) bool {
+		return !isAlphanumeric(prev) || !(isAlphanumeric(cur) || unicode.Is(unicode.Han, cur))
+	})
+	expect(t, "a4纸/", bytesToString(splitTextToWordsWithPolicy([]byte("A4纸"), skuUnit)))
+	expect(t, "xl码/，/", bytesToString(splitTextToWordsWithPolicy([]byte("XL码，"), skuUnit)))
+	expect(t, "yzl/-/1806052/", bytesToString(splitTextToWordsWithPolicy([]byte("YZL-1806052"), skuUnit)))
+
+	// 非法UTF8字节总是单独成为一个字元
+	never := BoundaryPolicyFunc(func(prev, cur rune) bool { return false })
+	expect(t, "ab/\xe4/cd/", bytesToString(splitTextToWordsWithPolicy([]byte("ab\xe4cd"), never)))
+
+	seg := NewSegmenter(WithBoundaryPolicy(letterDigit))
+	seg.LoadDictionary("iphone 10 nz\n手机壳 10 n\n")
+	segments := seg.Segment([]byte("iPhone14手机壳"))
+	expect(t, "iphone/nz 14/m 手机壳/n ", SegmentsToString(segments, false))
+	expect(t, "6", segments[1].start)
+	expect(t, "8", segments[1].end)
+}
+
+func TestGroupAlphanumeric(t *testing.T) {
+	grouped := NewSegmenter(WithGroupAlphanumeric(true))
+	grouped.LoadDictionary("abc 10 nz\n")
+	segments := grouped.Segment([]byte("ABC123"))
+	expect(t, "abc123/x ", SegmentsToString(segments, false))
+	expect(t, "0", segments[0].Start())
+	expect(t, "6", segments[0].End())
+
+	ungrouped := NewSegmenter(WithGroupAlphanumeric(false))
+	ungrouped.LoadDictionary("abc 10 nz\n")
+	segments = ungrouped.Segment([]byte("ABC123"))
+	expect(t, "abc/nz 1/m 2/m 3/m ", SegmentsToString(segments, false))
+	expect(t, "3", segments[1].Start())
+	expect(t, "4", segments[1].End())
+	expect(t, "5", segments[3].Start())
+	expect(t, "6", segments[3].End())
+	expect(t, "a/b/c/1/2/3/", bytesToString(ungrouped.splitText([]byte("abc123"))))
+
+	// 多字节字符的位置不受影响
+	segments = ungrouped.Segment([]byte("é中x"))
+	expect(t, "é/x 中/x x/x ", SegmentsToString(segments, false))
+	expect(t, "2", segments[1].Start())
+	expect(t, "5", segments[2].Start())
+}
@@ -0,0 +1,158 @@
+package sego
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// 缓存中每个分词大致占用的字节数，用于估算缓存的大小
+const cachedSegmentBytes = 48
+
+// 句子级的分词结果缓存，按最近最少使用的顺序淘汰，过期的条目不会命中
+type sentenceCache struct {
+	lock     sync.Mutex
+	maxBytes int
+	ttl      time.Duration
+	bytes    int
+	entries  map[string]*list.Element
+	lru      *list.List // 最近使用的条目在前
+	now      func() time.Time
+}
+
+// 缓存中的一个条目
+type cacheEntry struct {
+	key      string
+	segments []Segment
+	expires  time.Time
+	size     int
+}
+
+// 启用句子级的分词结果缓存
+//
+// 对同一段文本再次分词时直接返回缓存的结果而不运行最短路径算法，适合反复处理
+// 相同查询的场景（比如聊天机器人）。缓存以输入文本为键，总大小（文本长度加上
+// 分词结果的估算大小）超过maxBytes时淘汰最近最少使用的条目；条目在加入ttl
+// 时间后过期，ttl小于等于零时不过期。命中时返回结果的拷贝，调用者修改返回值
+// 不会影响缓存。
+//
+// 缓存对Segment、SegmentInto、SegmentWithPosition、SegmentHTML、InternalSegment
+// 和SegmentBatch有效。分词器的LoadDictionary、AddToken和ForceWord会清空缓存；
+// 直接修改词典（比如LoadVariantMap）后需要重新调用本函数。maxBytes小于等于零
+// 时关闭缓存。该函数不能和分词并发调用。
+func (seg *Segmenter) EnableSentenceCache(maxBytes int, ttl time.Duration) {
+	if maxBytes <= 0 {
+		seg.cache = nil
+		return
+	}
+	seg.cache = &sentenceCache{
+		maxBytes: maxBytes,
+		ttl:      ttl,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+		now:      time.Now,
+	}
+}
+
+// 缓存的键，区分是否为搜索模式
+func cacheKey(bytes []byte, searchMode bool) string {
+	if searchMode {
+		return "s" + string(bytes)
+	}
+	return "n" + string(bytes)
+}
+
+// 查找缓存，命中时返回结果的深拷贝（见copySegments），out不为nil时拷贝到out[:0]
+func (cache *sentenceCache) get(key string, out []Segment) ([]Segment, bool) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	element, ok := cache.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*cacheEntry)
+	if cache.ttl > 0 && !cache.now().Before(entry.expires) {
+		cache.remove(element)
+		return nil, false
+	}
+	cache.lru.MoveToFront(element)
+	return copySegments(out, entry.segments), true
+}
+
+// 加入缓存，大小超过上限的结果不缓存
+func (cache *sentenceCache) put(key string, segments []Segment) {
+	size := len(key) + len(segments)*cachedSegmentBytes
+	if size > cache.maxBytes {
+		return
+	}
+
+	// 伪分词的文本引用调用者的输入，需要拷贝
+	stored := copySegments(nil, segments)
+
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	if element, ok := cache.entries[key]; ok {
+		cache.remove(element)
+	}
+	entry := &cacheEntry{key: key, segments: stored, expires: cache.now().Add(cache.ttl), size: size}
+	cache.entries[key] = cache.lru.PushFront(entry)
+	cache.bytes += size
+	for cache.bytes > cache.maxBytes {
+		cache.remove(cache.lru.Back())
+	}
+}
+
+// 将segments深拷贝到dst[:0]，结果和segments不共享可以修改的数据
+//
+// 合并得到的分词的components逐层拷贝；不属于词典的Token（伪分词、合并或者
+// 词干化得到的分词）连同文本一起拷贝，因此拷贝不再引用分词的输入。词典中的
+// Token由词典持有，不拷贝。
+func copySegments(dst, segments []Segment) []Segment {
+	dst = append(dst[:0], segments...)
+	for i := range dst {
+		s := &dst[i]
+		if s.token.dict == nil {
+			token := *s.token
+			token.text = copyTexts(s.token.text)
+			s.token = &token
+		}
+		if s.components != nil {
+			s.components = copySegments(nil, s.components)
+		}
+	}
+	return dst
+}
+
+// 拷贝字元数组，所有字元共用一个新的缓冲区
+func copyTexts(text []Text) []Text {
+	length := 0
+	for _, word := range text {
+		length += len(word)
+	}
+	buffer := make([]byte, 0, length)
+	copied := make([]Text, len(text))
+	for i, word := range text {
+		buffer = append(buffer, word...)
+		copied[i] = buffer[len(buffer)-len(word) : len(buffer) : len(buffer)]
+	}
+	return copied
+}
+
+// 删除一个条目，调用时需要持有锁
+func (cache *sentenceCache) remove(element *list.Element) {
+	entry := cache.lru.Remove(element).(*cacheEntry)
+	delete(cache.entries, entry.key)
+	cache.bytes -= entry.size
+}
+
+// 清空缓存，cache为nil时不做任何事
+func (cache *sentenceCache) clear() {
+	if cache == nil {
+		return
+	}
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	cache.entries = make(map[string]*list.Element)
+	cache.lru.Init()
+	cache.bytes = 0
+}
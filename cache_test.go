@@ -0,0 +1,95 @@
+package sego
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSentenceCache(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n")
+	seg.EnableSentenceCache(1000, time.Minute)
+	now := time.Unix(0, 0)
+	seg.cache.now = func() time.Time { return now }
+
+	text := []byte("中国人口多")
+	expect(t, "中国/ns 人口/n 多/x ", SegmentsToString(seg.Segment(text), false))
+	expect(t, "1", len(seg.cache.entries))
+
+	// 修改输入和返回值都不影响缓存
+	text[len(text)-1] = 'x'
+	segments := seg.Segment([]byte("中国人口多"))
+	expect(t, "中国/ns 人口/n 多/x ", SegmentsToString(segments, false))
+	segments[0] = segments[1]
+	expect(t, "中国/ns 人口/n 多/x ", SegmentsToString(seg.Segment([]byte("中国人口多")), false))
+
+	// 搜索模式分别缓存
+	seg.InternalSegment([]byte("中国人口多"), true)
+	expect(t, "2", len(seg.cache.entries))
+
+	// 过期
+	now = now.Add(time.Minute)
+	_, ok := seg.cache.get(cacheKey([]byte("中国人口多"), false), nil)
+	expect(t, "false", ok)
+	expect(t, "1", len(seg.cache.entries))
+
+	// 改变词典后清空缓存
+	seg.AddToken("人口多", 100, "l")
+	expect(t, "0", len(seg.cache.entries))
+	expect(t, "中国/ns 人口多/l ", SegmentsToString(seg.Segment([]byte("中国人口多")), false))
+}
+
+func TestSentenceCacheDeepCopy(t *testing.T) {
+	seg := NewSegmenter(WithCollapseRepeats(true))
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n")
+	seg.EnableSentenceCache(1000, 0)
+
+	text := []byte("中国人口！！！多")
+	expected := "中国/ns 人口/n ！！！/w 多/x "
+	expect(t, expected, SegmentsToString(seg.Segment(text), false))
+
+	// 修改命中缓存返回的分词、伪分词的Token以及合并前的原分词
+	segments := seg.Segment(text)
+	expect(t, expected, SegmentsToString(segments, false))
+	components := segments[2].Components()
+	expect(t, "3", len(components))
+	components[0].start = 100
+	*components[1].token = Token{}
+	segments[2].components[2] = segments[0]
+	segments[2].token.text[0][0] = 'x'
+	*segments[3].token = Token{}
+
+	segments = seg.Segment(text)
+	expect(t, expected, SegmentsToString(segments, false))
+	expect(t, "！/w ！/w ！/w ", SegmentsToString(segments[2].Components(), false))
+	expect(t, "12", segments[2].Components()[0].start)
+
+	// 词典中的分词仍然共享
+	expect(t, "true", segments[0].token == seg.Segment(text)[0].token)
+}
+
+func TestSentenceCacheEviction(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n国人 10 n\n")
+
+	// 每个条目的大小为1+6+48=55字节
+	seg.EnableSentenceCache(120, 0)
+	seg.Segment([]byte("中国"))
+	seg.Segment([]byte("人口"))
+	seg.Segment([]byte("中国"))
+	seg.Segment([]byte("国人"))
+	expect(t, "2", len(seg.cache.entries))
+	expect(t, "110", seg.cache.bytes)
+	_, ok := seg.cache.get(cacheKey([]byte("人口"), false), nil)
+	expect(t, "false", ok)
+	_, ok = seg.cache.get(cacheKey([]byte("中国"), false), nil)
+	expect(t, "true", ok)
+
+	// 超过上限的结果不缓存
+	seg.Segment([]byte("中国人口中国人口中国人口"))
+	expect(t, "2", len(seg.cache.entries))
+
+	seg.EnableSentenceCache(0, 0)
+	expect(t, "true", seg.cache == nil)
+	expect(t, "中国/ns ", SegmentsToString(seg.Segment([]byte("中国")), false))
+}
@@ -0,0 +1,118 @@
+package sego
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 按词性序列识别短语（比如名词短语）的组块分析器
+//
+// 零值的Chunker没有任何模式，不识别任何短语。
+type Chunker struct {
+	patterns []chunkPattern
+}
+
+// 组块分析识别出的一个短语，位置是分词在[]TaggedSegment中的下标
+type Chunk struct {
+	Start int    // 短语第一个分词的下标
+	End   int    // 短语最后一个分词之后的下标（不包括该分词）
+	Type  string // 短语类型，即匹配的模式的类型，比如"NP"
+}
+
+// 组块模式中的一个元素：词性前缀及其重复次数
+type chunkElement struct {
+	pos      string
+	min, max int // max小于零表示不限次数
+}
+
+type chunkPattern struct {
+	chunkType string
+	elements  []chunkElement
+}
+
+// 创建一个识别名词短语的组块分析器
+//
+// 名词短语的模式为"a* n+"，即任意个形容词后面跟一个或多个名词（包括地名、
+// 人名等名词的子类，见POS.IsA），类型为"NP"。
+func NewNounPhraseChunker() *Chunker {
+	chunker := &Chunker{}
+	chunker.AddPattern("NP", "a* n+")
+	return chunker
+}
+
+// 加入一个类型为chunkType的模式
+//
+// 模式是空格分隔的词性列表，每个词性按前缀匹配（见POS.IsA），后面可以跟
+// "?"（零或一次）、"*"（零或多次）、"+"（一或多次）表示重复次数，比如"a? n+"。
+// 模式为空或者只能匹配零个分词时返回错误。
+func (chunker *Chunker) AddPattern(chunkType, pattern string) error {
+	fields := strings.Fields(pattern)
+	elements := make([]chunkElement, len(fields))
+	minLength := 0
+	for i, field := range fields {
+		element := chunkElement{pos: field, min: 1, max: 1}
+		switch field[len(field)-1] {
+		case '?':
+			element = chunkElement{pos: field[:len(field)-1], min: 0, max: 1}
+		case '*':
+			element = chunkElement{pos: field[:len(field)-1], min: 0, max: -1}
+		case '+':
+			element = chunkElement{pos: field[:len(field)-1], min: 1, max: -1}
+		}
+		if element.pos == "" {
+			return fmt.Errorf("sego: 组块模式\"%s\"中缺少词性", pattern)
+		}
+		minLength += element.min
+		elements[i] = element
+	}
+	if minLength == 0 {
+		return fmt.Errorf("sego: 组块模式\"%s\"可以匹配空序列", pattern)
+	}
+	chunker.patterns = append(chunker.patterns, chunkPattern{chunkType, elements})
+	return nil
+}
+
+// 识别分词序列中的短语
+//
+// 从左到右扫描，在每个位置按加入的顺序尝试所有模式，取匹配最长的一个（长度
+// 相同时取先加入的），然后从短语之后继续扫描，因此返回的短语互不重叠且按位置
+// 排列。
+func (chunker *Chunker) Chunks(segs []TaggedSegment) []Chunk {
+	output := []Chunk{}
+	for start := 0; start < len(segs); {
+		best := Chunk{Start: start, End: start}
+		for _, pattern := range chunker.patterns {
+			if end := matchChunk(pattern.elements, segs, start); end > best.End {
+				best = Chunk{Start: start, End: end, Type: pattern.chunkType}
+			}
+		}
+		if best.End == start {
+			start++
+			continue
+		}
+		output = append(output, best)
+		start = best.End
+	}
+	return output
+}
+
+// 返回从下标start开始匹配elements的最长的结束下标，无法匹配时返回-1
+func matchChunk(elements []chunkElement, segs []TaggedSegment, start int) int {
+	if len(elements) == 0 {
+		return start
+	}
+	element := elements[0]
+
+	// 先数出当前元素最多能连续匹配多少个分词，再从多到少回溯
+	count := 0
+	for start+count < len(segs) && (element.max < 0 || count < element.max) &&
+		POS(segs[start+count].POS()).IsA(element.pos) {
+		count++
+	}
+	for ; count >= element.min; count-- {
+		if end := matchChunk(elements[1:], segs, start+count); end >= 0 {
+			return end
+		}
+	}
+	return -1
+}
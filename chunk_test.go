@@ -0,0 +1,52 @@
+package sego
+
+import (
+	"fmt"
+	"testing"
+)
+
+func chunksToString(segs []TaggedSegment, chunks []Chunk) (output string) {
+	for _, chunk := range chunks {
+		output += chunk.Type + ":"
+		for _, s := range segs[chunk.Start:chunk.End] {
+			output += s.Token().Text()
+		}
+		output += " "
+	}
+	return
+}
+
+func TestNounPhraseChunker(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("美丽 10 a\n中国 10 ns\n城市 10 n\n很 10 d\n多 10 m\n的 10 uj\n古老 10 a\n建筑 10 n\n")
+	segs := seg.SegmentPOS([]byte("美丽中国城市的古老建筑很多，古老的美丽"))
+
+	chunker := NewNounPhraseChunker()
+	chunks := chunker.Chunks(segs)
+	expect(t, "NP:美丽中国城市 NP:古老建筑 ", chunksToString(segs, chunks))
+	expect(t, "[{0 3 NP} {4 6 NP}]", fmt.Sprint(chunks))
+
+	var empty Chunker
+	expect(t, "0", len(empty.Chunks(segs)))
+}
+
+func TestChunkerPatterns(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("美丽 10 a\n中国 10 ns\n城市 10 n\n的 10 uj\n古老 10 a\n建筑 10 n\n")
+	segs := seg.SegmentPOS([]byte("美丽的中国城市古老的建筑"))
+
+	var chunker Chunker
+	expect(t, "<nil>", chunker.AddPattern("DNP", "a uj n+"))
+	expect(t, "<nil>", chunker.AddPattern("NP", "n+"))
+	expect(t, "DNP:美丽的中国城市 DNP:古老的建筑 ", chunksToString(segs, chunker.Chunks(segs)))
+
+	// 回溯：n*之后还需要一个ns
+	var backtrack Chunker
+	backtrack.AddPattern("LOC", "n* ns")
+	segs = seg.SegmentPOS([]byte("城市中国建筑"))
+	expect(t, "LOC:城市中国 ", chunksToString(segs, backtrack.Chunks(segs)))
+
+	expect(t, "sego: 组块模式\"a* n?\"可以匹配空序列", chunker.AddPattern("X", "a* n?"))
+	expect(t, "sego: 组块模式\"+\"中缺少词性", chunker.AddPattern("X", "+"))
+	expect(t, "sego: 组块模式\"\"可以匹配空序列", chunker.AddPattern("X", ""))
+}
@@ -0,0 +1,53 @@
+package sego
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// 对文本分词，并为连续的中日韩单字伪分词额外输出n元组，用于提高搜索的召回率
+//
+// 词典中没有的多字词会被切成单字伪分词（词性为"x"），只用单字建索引时搜索
+// 这样的词效果很差。该函数在正常分词结果的基础上，对每段连续的中日韩单字伪
+// 分词再输出所有相邻n个字组成的分词，比如n为2时"甲乙丙"额外输出"甲乙"和
+// "乙丙"。n元组的词性为"x"，位置是在输入文本中的实际位置。
+//
+// 结果按起始位置排序，起始位置相同时原分词在前。n小于2时和Segment相同。
+func (seg *Segmenter) SegmentCJKNgram(bytes []byte, n int) []Segment {
+	segments := seg.Segment(bytes)
+	if n < 2 {
+		return segments
+	}
+
+	output := make([]Segment, 0, len(segments))
+	for start := 0; start < len(segments); {
+		if !isCJKPseudoSegment(segments[start]) {
+			output = append(output, segments[start])
+			start++
+			continue
+		}
+
+		// 找到一段连续的单字伪分词
+		end := start + 1
+		for end < len(segments) && isCJKPseudoSegment(segments[end]) {
+			end++
+		}
+		for i := start; i < end; i++ {
+			output = append(output, segments[i])
+			if i+n <= end {
+				output = append(output, mergeSegments(segments[i:i+n], segments[i].token))
+			}
+		}
+		start = end
+	}
+	return output
+}
+
+// 判断分词是否为中日韩文字的单字伪分词
+func isCJKPseudoSegment(s Segment) bool {
+	if s.token.kind != KindPseudo || len(s.token.text) != 1 {
+		return false
+	}
+	r, _ := utf8.DecodeRune(s.token.text[0])
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
@@ -0,0 +1,25 @@
+package sego
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSegmentCJKNgram(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n")
+	text := []byte("中国饕餮盛宴，A股")
+
+	expect(t, "中国/ns 饕/x 饕餮/x 餮/x 餮盛/x 盛/x 盛宴/x 宴/x ，/w a/x 股/x ",
+		SegmentsToString(seg.SegmentCJKNgram(text, 2), false))
+	expect(t, "中国/ns 饕/x 饕餮盛/x 餮/x 餮盛宴/x 盛/x 宴/x ，/w a/x 股/x ",
+		SegmentsToString(seg.SegmentCJKNgram(text, 3), false))
+	expect(t, "中国/ns 饕/x 餮/x 盛/x 宴/x ，/w a/x 股/x ",
+		SegmentsToString(seg.SegmentCJKNgram(text, 5), false))
+	expect(t, SegmentsToString(seg.Segment(text), false),
+		SegmentsToString(seg.SegmentCJKNgram(text, 1), false))
+
+	segments := seg.SegmentCJKNgram(text, 2)
+	expect(t, "6 12 2 4", fmt.Sprint(segments[2].start, segments[2].end, segments[2].runeStart, segments[2].runeEnd))
+	expect(t, "9 15 3 5", fmt.Sprint(segments[4].start, segments[4].end, segments[4].runeStart, segments[4].runeEnd))
+}
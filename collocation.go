@@ -0,0 +1,73 @@
+package sego
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// 一个搭配及其得分，见FindCollocations
+type Collocation struct {
+	Words []string
+	Score float64
+}
+
+// 在语料库中寻找经常相邻出现的两个词，可以作为加入词典的候选
+//
+// 语料库中的每篇文档用seg分词（使用SegmentBatch并行分词），相邻的词组成
+// 二元组，二元组不跨越文档，含有空白或标点分词的二元组不计入。出现次数少于
+// minFreq的二元组被忽略，其余按点互信息打分：
+//
+//	PMI(w1,w2) = log(P(w1,w2) / (P(w1)P(w2)))
+//
+// 其中P(w)为w在所有分词中出现的比例，P(w1,w2)为二元组在所有二元组中出现的
+// 比例。返回得分最高的topN个搭配，按得分从高到低排序，得分相同时按文本的
+// 字典序。topN小于等于零时返回所有搭配。
+func FindCollocations(corpus [][]byte, seg *Segmenter, minFreq int, topN int) []Collocation {
+	unigrams := make(map[string]int)
+	bigrams := make(map[[2]string]int)
+	numUnigrams, numBigrams := 0, 0
+	for _, segments := range seg.SegmentBatch(corpus, 0) {
+		words := SegmentsToSlice(segments, false)
+		for i, word := range words {
+			unigrams[word]++
+			numUnigrams++
+			if i > 0 && isCollocationWord(words[i-1]) && isCollocationWord(word) {
+				bigrams[[2]string{words[i-1], word}]++
+				numBigrams++
+			}
+		}
+	}
+
+	output := []Collocation{}
+	for bigram, count := range bigrams {
+		if count < minFreq {
+			continue
+		}
+		p12 := float64(count) / float64(numBigrams)
+		p1 := float64(unigrams[bigram[0]]) / float64(numUnigrams)
+		p2 := float64(unigrams[bigram[1]]) / float64(numUnigrams)
+		output = append(output, Collocation{
+			Words: []string{bigram[0], bigram[1]},
+			Score: math.Log(p12 / (p1 * p2)),
+		})
+	}
+	sort.Slice(output, func(i, j int) bool {
+		if output[i].Score != output[j].Score {
+			return output[i].Score > output[j].Score
+		}
+		if output[i].Words[0] != output[j].Words[0] {
+			return output[i].Words[0] < output[j].Words[0]
+		}
+		return output[i].Words[1] < output[j].Words[1]
+	})
+	if topN > 0 && topN < len(output) {
+		output = output[:topN]
+	}
+	return output
+}
+
+// 判断分词是否可以作为搭配的一部分，空白和标点不可以
+func isCollocationWord(word string) bool {
+	return strings.TrimSpace(word) != "" && !isPunctText(word)
+}
@@ -0,0 +1,28 @@
+package sego
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFindCollocations(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("自然 10 n\n语言 10 n\n处理 10 v\n数据 10 n\n的 10 uj\n")
+	corpus := [][]byte{
+		[]byte("自然语言处理的数据"),
+		[]byte("自然语言，数据的处理"),
+		[]byte("语言的数据"),
+	}
+
+	collocations := FindCollocations(corpus, &seg, 2, 0)
+	output := ""
+	for _, c := range collocations {
+		output += fmt.Sprintf("%v:%.3f ", c.Words, c.Score)
+	}
+	// 共14个分词、9个不含标点的二元组：自然语言出现2次，P=2/9，P(自然)=2/14，P(语言)=3/14
+	expect(t, "[自然 语言]:1.982 [的 数据]:1.577 ", output)
+
+	expect(t, "1", len(FindCollocations(corpus, &seg, 2, 1)))
+	expect(t, "0", len(FindCollocations(corpus, &seg, 3, 0)))
+	expect(t, "0", len(FindCollocations(nil, &seg, 1, 0)))
+}
@@ -0,0 +1,66 @@
+package sego
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// 以CoNLL格式输出分词结果
+//
+// 每个分词一行，依次为从1开始的序号、分词文本和词性，用制表符分隔，没有词性时
+// 输出"_"。每个句子之后输出一个空行，句子的划分规则和SplitSentences相同，句末
+// 的后引号和后括号归入前一个句子。只包含空白字符的分词不输出。
+func WriteCoNLL(w io.Writer, segs []Segment) error {
+	writer := bufio.NewWriter(w)
+	index := 0
+	sentenceEnded := false
+	for i, s := range segs {
+		text := textSliceToString(s.token.text)
+		if strings.TrimSpace(text) == "" {
+			if strings.Contains(text, "\n") {
+				sentenceEnded = true
+			}
+			continue
+		}
+
+		if sentenceEnded && !isSentenceTrailerText(text) {
+			if index > 0 {
+				writer.WriteString("\n")
+				index = 0
+			}
+			sentenceEnded = false
+		}
+
+		index++
+		pos := s.token.pos
+		if pos == "" {
+			pos = "_"
+		}
+		writer.WriteString(strconv.Itoa(index) + "\t" + text + "\t" + pos + "\n")
+
+		var next []byte
+		if i+1 < len(segs) {
+			next = textSliceToBytes(segs[i+1].token.text)
+		}
+		if r, _ := utf8.DecodeLastRuneInString(text); isSentenceTerminator(r, next) {
+			sentenceEnded = true
+		}
+	}
+	if index > 0 {
+		writer.WriteString("\n")
+	}
+	return writer.Flush()
+}
+
+// 判断文本是否只包含句子结束符、后引号和后括号，这样的分词归入前一个句子
+func isSentenceTrailerText(text string) bool {
+	for _, r := range text {
+		if !isSentenceTerminator(r, nil) && !isSentenceTrailer(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,36 @@
+package sego
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestWriteCoNLL(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n众多 10 a\n他 10 r\n说 10 v\n")
+
+	var buf bytes.Buffer
+	expect(t, "<nil>", WriteCoNLL(&buf, seg.Segment([]byte("他说：“中国人口众多。”人口 众多\n中国"))))
+	expect(t, "1\t他\tr\n2\t说\tv\n3\t：\tw\n4\t“\tw\n5\t中国\tns\n6\t人口\tn\n7\t众多\ta\n8\t。\tw\n9\t”\tw\n\n"+
+		"1\t人口\tn\n2\t众多\ta\n\n"+
+		"1\t中国\tns\n\n", buf.String())
+
+	// 没有词性时输出"_"
+	buf.Reset()
+	seg.AddToken("十三亿", 10, "")
+	expect(t, "<nil>", WriteCoNLL(&buf, seg.Segment([]byte("十三亿"))))
+	expect(t, "1\t十三亿\t_\n\n", buf.String())
+
+	buf.Reset()
+	expect(t, "<nil>", WriteCoNLL(&buf, nil))
+	expect(t, "", buf.String())
+
+	expect(t, "写入失败", WriteCoNLL(failingWriter{}, seg.Segment([]byte("中国"))))
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("写入失败")
+}
@@ -0,0 +1,79 @@
+package sego
+
+import (
+	"sort"
+)
+
+// 词共现矩阵，记录两个词在一定窗口内同时出现的次数
+//
+// 矩阵是对称的，Get(w1, w2)和Get(w2, w1)相等。
+type CooccurrenceMatrix struct {
+	counts map[string]map[string]int
+}
+
+// 统计多篇文档中每对词在windowSize个词之内同时出现的次数
+//
+// 文档用seg分词（使用SegmentBatch并行分词），窗口不跨越文档。空白和标点不算作
+// 词（见isCollocationWord），先去掉再按窗口统计，比如windowSize为1时只统计
+// 去掉空白和标点后相邻的词。同一个词在窗口内出现两次时也计入它和自身的共现
+// 次数。windowSize小于等于零时矩阵为空。
+func BuildCooccurrence(documents [][]byte, seg *Segmenter, windowSize int) *CooccurrenceMatrix {
+	matrix := &CooccurrenceMatrix{counts: make(map[string]map[string]int)}
+	if windowSize <= 0 {
+		return matrix
+	}
+	for _, segments := range seg.SegmentBatch(documents, 0) {
+		words := make([]string, 0, len(segments))
+		for _, word := range SegmentsToSlice(segments, false) {
+			if isCollocationWord(word) {
+				words = append(words, word)
+			}
+		}
+		for i, w1 := range words {
+			for j := i + 1; j < len(words) && j <= i+windowSize; j++ {
+				w2 := words[j]
+				matrix.add(w1, w2)
+				if w1 != w2 {
+					matrix.add(w2, w1)
+				}
+			}
+		}
+	}
+	return matrix
+}
+
+func (matrix *CooccurrenceMatrix) add(w1, w2 string) {
+	row, ok := matrix.counts[w1]
+	if !ok {
+		row = make(map[string]int)
+		matrix.counts[w1] = row
+	}
+	row[w2]++
+}
+
+// 返回w1和w2同时出现的次数
+func (matrix *CooccurrenceMatrix) Get(w1, w2 string) int {
+	return matrix.counts[w1][w2]
+}
+
+// 返回和word同时出现次数最多的n个词，按次数从多到少排序，次数相同时按字典序
+//
+// 不足n个时返回所有和word同时出现过的词。
+func (matrix *CooccurrenceMatrix) TopNeighbors(word string, n int) []string {
+	row := matrix.counts[word]
+	neighbors := make([]string, 0, len(row))
+	for neighbor := range row {
+		neighbors = append(neighbors, neighbor)
+	}
+	sort.Slice(neighbors, func(i, j int) bool {
+		ci, cj := row[neighbors[i]], row[neighbors[j]]
+		if ci != cj {
+			return ci > cj
+		}
+		return neighbors[i] < neighbors[j]
+	})
+	if n >= 0 && n < len(neighbors) {
+		neighbors = neighbors[:n]
+	}
+	return neighbors
+}
@@ -0,0 +1,40 @@
+package sego
+
+import (
+	"testing"
+)
+
+func TestCooccurrence(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n众多 10 a\n经济 10 n\n发展 10 v\n")
+	documents := [][]byte{
+		[]byte("中国人口众多"),
+		[]byte("中国经济发展"),
+		[]byte("人口中国"),
+	}
+
+	matrix := BuildCooccurrence(documents, &seg, 1)
+	expect(t, "2", matrix.Get("中国", "人口"))
+	expect(t, "2", matrix.Get("人口", "中国"))
+	expect(t, "0", matrix.Get("中国", "众多"))
+	expect(t, "0", matrix.Get("发展", "人口"))
+	expect(t, "[人口 经济]", matrix.TopNeighbors("中国", 5))
+	expect(t, "[人口]", matrix.TopNeighbors("中国", 1))
+	expect(t, "[]", matrix.TopNeighbors("日本", 5))
+
+	matrix = BuildCooccurrence(documents, &seg, 2)
+	expect(t, "1", matrix.Get("中国", "众多"))
+	expect(t, "1", matrix.Get("中国", "发展"))
+	expect(t, "[人口 众多 发展 经济]", matrix.TopNeighbors("中国", 10))
+
+	// 窗口不跨越文档
+	expect(t, "0", matrix.Get("发展", "人口"))
+	expect(t, "0", BuildCooccurrence(documents, &seg, 0).Get("中国", "人口"))
+
+	// 空白和标点不计入，也不占窗口的位置
+	matrix = BuildCooccurrence([][]byte{[]byte("中国，人口。 众多！")}, &seg, 1)
+	expect(t, "1", matrix.Get("中国", "人口"))
+	expect(t, "1", matrix.Get("人口", "众多"))
+	expect(t, "[人口]", matrix.TopNeighbors("中国", 10))
+	expect(t, "[]", matrix.TopNeighbors("，", 10))
+}
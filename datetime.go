@@ -0,0 +1,299 @@
+package sego
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// 文本中的一个日期时间表达式，见ExtractDatetime
+type DatetimeEntity struct {
+	Text  string // 表达式的文本
+	Start int    // 在文本中的起始字节位置
+	End   int    // 在文本中的结束字节位置（不包括该位置）
+
+	// 解析出的时间，只有年月日都能确定时才有值，否则为零值。没有给出时刻时
+	// 为当天零点，时区为参考时间的时区
+	Parsed time.Time
+}
+
+// 可能出现在日期时间表达式中的字，词性为"t"（时间词）或"m"（数词）的分词也
+// 可能是表达式的一部分
+const datetimeChars = chineseDigits + "十两年月日号点时分秒半周星期礼拜天今明昨前后大上下本这去午晚早中凌晨"
+
+// 中文数字，按数值排列，"〇"和"零"相同，用于逐位读出的年份，比如"二〇二四"
+const chineseDigits = "零一二三四五六七八九〇"
+
+// 从文本中提取日期时间表达式，相对表达式以当前时间为参考，见ExtractDatetimeAt
+func ExtractDatetime(text []byte, seg *Segmenter) []DatetimeEntity {
+	return ExtractDatetimeAt(text, seg, time.Now())
+}
+
+// 从文本中提取日期时间表达式，相对表达式（比如"明天"、"上周五"）以now为参考
+//
+// 文本先用seg分词，再把连续的可能属于日期时间表达式的分词（时间词、数词以及
+// 只由数字和日期时间单位组成的分词）拼接起来解析，因此表达式的边界总是分词的
+// 边界。支持的格式有：
+//
+//	绝对日期：2024年3月1日、二零二四年三月一日、3月1号、2024年
+//	相对日期：今天、明天、后天、昨天、前天、今年三月五日、上周五、下星期一
+//	时刻：下午3点、3点半、十点二十分、15点30分10秒，可以跟在日期之后
+//
+// 一周从周一开始，"周日"是本周的最后一天。只有时刻、没有年份或者年份只有两位
+// 的表达式不能确定日期，Parsed为零值。
+func ExtractDatetimeAt(text []byte, seg *Segmenter, now time.Time) []DatetimeEntity {
+	segments := seg.Segment(text)
+	output := []DatetimeEntity{}
+	for i := 0; i < len(segments); {
+		if !isDatetimeSegment(&segments[i]) {
+			i++
+			continue
+		}
+		j := i + 1
+		for j < len(segments) && isDatetimeSegment(&segments[j]) {
+			j++
+		}
+
+		// 从最长的分词序列开始尝试，取能完整解析的最长的一个
+		found := false
+		for k := j; k > i; k-- {
+			start, end := segments[i].start, segments[k-1].end
+			// 分词位置相对于原文本，解析规范化之后的文本（比如全角数字转换为半角）
+			parsed, ok := parseDatetime([]rune(string(seg.normalizeText(text[start:end]))), now)
+			if ok {
+				output = append(output, DatetimeEntity{Text: string(text[start:end]),
+					Start: start, End: end, Parsed: parsed})
+				i = k
+				found = true
+				break
+			}
+		}
+		if !found {
+			i++
+		}
+	}
+	return output
+}
+
+// 判断分词是否可能是日期时间表达式的一部分
+func isDatetimeSegment(s *Segment) bool {
+	pos := POS(s.token.pos)
+	if pos.IsA("t") || pos.IsA("m") {
+		return true
+	}
+	for _, word := range s.token.text {
+		for _, r := range string(word) {
+			if !unicode.IsDigit(r) && !strings.ContainsRune(datetimeChars, r) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// 日期时间表达式的解析器
+type datetimeParser struct {
+	text []rune
+	pos  int
+}
+
+// 如果当前位置以s开头则跳过s并返回true
+func (p *datetimeParser) consume(s string) bool {
+	runes := []rune(s)
+	if len(p.text)-p.pos < len(runes) || string(p.text[p.pos:p.pos+len(runes)]) != s {
+		return false
+	}
+	p.pos += len(runes)
+	return true
+}
+
+// 依次尝试words，跳过第一个匹配的词并返回其下标，都不匹配时返回-1
+func (p *datetimeParser) consumeAny(words ...string) int {
+	for i, word := range words {
+		if p.consume(word) {
+			return i
+		}
+	}
+	return -1
+}
+
+// 读取一个不超过99的数，比如"3"、"12"、"三"、"十"、"二十三"，失败时不移动位置
+func (p *datetimeParser) number() (int, bool) {
+	start := p.pos
+	value := 0
+	for p.pos < len(p.text) && p.text[p.pos] >= '0' && p.text[p.pos] <= '9' {
+		value = value*10 + int(p.text[p.pos]-'0')
+		p.pos++
+	}
+	if p.pos > start {
+		return value, p.pos-start <= 2
+	}
+
+	tens, ones := 0, -1
+	if d := p.chineseDigit(); d > 0 {
+		ones = d
+	}
+	if p.consume("十") {
+		tens = 1
+		if ones > 0 {
+			tens = ones
+		}
+		ones = 0
+		if d := p.chineseDigit(); d > 0 {
+			ones = d
+		}
+	}
+	if ones < 0 {
+		p.pos = start
+		return 0, false
+	}
+	return tens*10 + ones, true
+}
+
+// 读取一个中文数字，不是中文数字时返回-1且不移动位置
+func (p *datetimeParser) chineseDigit() int {
+	if p.pos >= len(p.text) {
+		return -1
+	}
+	r := p.text[p.pos]
+	if r == '两' {
+		p.pos++
+		return 2
+	}
+	if i := strings.IndexRune(chineseDigits, r); i >= 0 {
+		p.pos++
+		return i / len("零") % 10
+	}
+	return -1
+}
+
+// 读取"年"之前的年份，返回年份和位数，失败时不移动位置
+func (p *datetimeParser) year() (int, int, bool) {
+	start := p.pos
+	value, digits := 0, 0
+	for p.pos < len(p.text) {
+		if r := p.text[p.pos]; r >= '0' && r <= '9' {
+			value = value*10 + int(r-'0')
+			p.pos++
+		} else if d := p.chineseDigit(); d >= 0 {
+			value = value*10 + d
+		} else {
+			break
+		}
+		digits++
+	}
+	if (digits == 2 || digits == 4) && p.consume("年") {
+		return value, digits, true
+	}
+	p.pos = start
+	return 0, 0, false
+}
+
+// 解析整个文本，文本不是完整的日期时间表达式时返回false
+func parseDatetime(text []rune, now time.Time) (time.Time, bool) {
+	p := &datetimeParser{text: text}
+	year, month, day := 0, 0, 0
+	dateKnown, hasDate := false, false
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	if i := p.consumeAny("大前天", "大后天", "前天", "后天", "昨天", "明天", "今天"); i >= 0 {
+		date := today.AddDate(0, 0, []int{-3, 3, -2, 2, -1, 1, 0}[i])
+		year, month, day = date.Year(), int(date.Month()), date.Day()
+		dateKnown, hasDate = true, true
+	} else if date, ok := p.weekday(today); ok {
+		year, month, day = date.Year(), int(date.Month()), date.Day()
+		dateKnown, hasDate = true, true
+	} else {
+		yearKnown := false
+		if i := p.consumeAny("前年", "去年", "今年", "明年", "后年"); i >= 0 {
+			year, yearKnown, hasDate = now.Year()+i-2, true, true
+		} else if value, digits, ok := p.year(); ok {
+			year, yearKnown, hasDate = value, digits == 4, true
+		}
+		start := p.pos
+		if value, ok := p.number(); ok && value >= 1 && value <= 12 && p.consume("月") {
+			month, hasDate = value, true
+			start = p.pos
+			if value, ok := p.number(); ok && value >= 1 && value <= 31 && p.consumeAny("日", "号") >= 0 {
+				day = value
+			} else {
+				p.pos = start
+			}
+		} else {
+			p.pos = start
+		}
+		dateKnown = yearKnown && month > 0 && day > 0
+	}
+
+	hour, minute, second, hasTime := p.clock()
+	if p.pos != len(p.text) || (!hasDate && !hasTime) {
+		return time.Time{}, false
+	}
+	if !dateKnown {
+		return time.Time{}, true
+	}
+	parsed := time.Date(year, time.Month(month), day, hour, minute, second, 0, now.Location())
+	if parsed.Day() != day {
+		// 不存在的日期，比如2月30日
+		return time.Time{}, true
+	}
+	return parsed, true
+}
+
+// 读取"上周五"、"星期日"这样的表达式，返回对应的日期
+func (p *datetimeParser) weekday(today time.Time) (time.Time, bool) {
+	start := p.pos
+	weeks := 0
+	if i := p.consumeAny("上", "下", "本", "这"); i >= 0 {
+		weeks = []int{-1, 1, 0, 0}[i]
+	}
+	if p.consumeAny("周", "星期", "礼拜") < 0 {
+		p.pos = start
+		return time.Time{}, false
+	}
+	day := p.consumeAny("一", "二", "三", "四", "五", "六", "日", "天")
+	if day < 0 {
+		p.pos = start
+		return time.Time{}, false
+	}
+	if day == 7 {
+		day = 6
+	}
+	// 本周一为today减去今天是星期几（周一为0）
+	monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	return monday.AddDate(0, 0, weeks*7+day), true
+}
+
+// 读取时刻，比如"下午3点半"，没有时刻时返回false且不移动位置
+func (p *datetimeParser) clock() (hour, minute, second int, ok bool) {
+	start := p.pos
+	period := p.consumeAny("凌晨", "早上", "上午", "中午", "下午", "晚上")
+	hour, ok = p.number()
+	if !ok || hour > 23 || p.consumeAny("点", "时") < 0 {
+		p.pos = start
+		return 0, 0, 0, false
+	}
+	switch {
+	case (period == 4 || period == 5) && hour < 12:
+		hour += 12
+	case period == 3 && hour < 11:
+		hour += 12
+	}
+
+	if p.consume("半") {
+		return hour, 30, 0, true
+	}
+	afterHour := p.pos
+	if value, ok := p.number(); ok && value < 60 && p.consume("分") {
+		minute = value
+		afterMinute := p.pos
+		if value, ok := p.number(); ok && value < 60 && p.consume("秒") {
+			second = value
+		} else {
+			p.pos = afterMinute
+		}
+	} else {
+		p.pos = afterHour
+	}
+	return hour, minute, second, true
+}
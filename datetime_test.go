@@ -0,0 +1,53 @@
+package sego
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func datetimesToString(entities []DatetimeEntity) (output string) {
+	for _, e := range entities {
+		parsed := "?"
+		if !e.Parsed.IsZero() {
+			parsed = e.Parsed.Format("2006-01-02 15:04:05")
+		}
+		output += fmt.Sprintf("%s[%d:%d]=%s ", e.Text, e.Start, e.End, parsed)
+	}
+	return
+}
+
+func TestExtractDatetime(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("明天 10 t\n下午 10 t\n开会 10 v\n我们 10 r\n上周五 10 t\n三月 10 t\n出生 10 v\n于 10 p\n")
+	// 2024年3月13日是星期三
+	now := time.Date(2024, 3, 13, 9, 30, 0, 0, time.UTC)
+
+	cases := []struct{ text, want string }{
+		{"我们明天下午3点开会", "明天下午3点[6:22]=2024-03-14 15:00:00 "},
+		{"出生于2024年3月1日", "2024年3月1日[9:24]=2024-03-01 00:00:00 "},
+		{"出生于二零二四年三月一日", "二零二四年三月一日[9:36]=2024-03-01 00:00:00 "},
+		{"二〇二四年十二月三十一日晚上十点半", "二〇二四年十二月三十一日晚上十点半[0:51]=2024-12-31 22:30:00 "},
+		{"上周五开会，下星期日开会", "上周五[0:9]=2024-03-08 00:00:00 下星期日[18:30]=2024-03-24 00:00:00 "},
+		{"今年三月五号", "今年三月五号[0:18]=2024-03-05 00:00:00 "},
+		{"昨天15点30分10秒", "昨天15点30分10秒[0:21]=2024-03-12 15:30:10 "},
+		{"三月开会，下午两点开会", "三月[0:6]=? 下午两点[15:27]=? "},
+		{"24年3月1日", "24年3月1日[0:13]=? "},
+		{"2024年2月30日", "2024年2月30日[0:16]=? "},
+		{"我们开会", ""},
+		{"十三亿", ""},
+	}
+	for _, c := range cases {
+		expect(t, c.want, datetimesToString(ExtractDatetimeAt([]byte(c.text), &seg, now)))
+	}
+
+	// 规范化改变了文本长度时位置仍然对应原文本
+	fwSeg := NewSegmenter(WithFullWidthNormalization())
+	fwSeg.LoadDictionary("出生 10 v\n于 10 p\n")
+	expect(t, "２０２４年３月１日[9:36]=2024-03-01 00:00:00 ",
+		datetimesToString(ExtractDatetimeAt([]byte("出生于２０２４年３月１日"), fwSeg, now)))
+
+	// 以当前时间为参考
+	entities := ExtractDatetime([]byte("明天"), &seg)
+	expect(t, time.Now().AddDate(0, 0, 1).Format("2006-01-02"), entities[0].Parsed.Format("2006-01-02"))
+}
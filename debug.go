@@ -0,0 +1,37 @@
+package sego
+
+// Viterbi算法中某个字元处的跳转信息，见Segmenter.SegmentDebug
+type JumperInfo struct {
+	// 字元在文本中的序号，从0开始
+	Position int
+
+	// 从文本开始到该字元（含）的最短路径值
+	MinDistance float32
+
+	// 最短路径上以该字元结尾的分词的文本
+	TokenText string
+}
+
+// 对文本分词，同时返回Viterbi算法在每个字元处的跳转信息，用于调试和教学
+//
+// 跳转信息和分词结果来自同一次计算：分词结果正是从最后一个字元的跳转信息
+// 向前回溯得到的。字元的划分见splitTextToWords，比如一个英文词是一个字元。
+// SegmentDebug不处理AddProtectPattern加入的保护模式。
+func (seg *Segmenter) SegmentDebug(bytes []byte) ([]Segment, []JumperInfo) {
+	text, spans := seg.normalizeTextWithSpans(bytes)
+	if len(text) == 0 {
+		return []Segment{}, []JumperInfo{}
+	}
+	seg.dict.rebuildIfDirty()
+
+	jumpers := seg.computeJumpers(seg.splitText(text), false)
+	infos := make([]JumperInfo, len(jumpers))
+	for i, j := range jumpers {
+		infos[i] = JumperInfo{Position: i, MinDistance: j.minDistance, TokenText: j.token.Text()}
+	}
+	segments := segmentsFromJumpers(jumpers, nil)
+	if spans != nil {
+		segments = remapSegments(segments, spans, bytes)
+	}
+	return segments, infos
+}
@@ -0,0 +1,23 @@
+package sego
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSegmentDebug(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 2 ns\n人口 2 n\n国人 4 n\n")
+
+	segments, infos := seg.SegmentDebug([]byte("中国人口"))
+	expect(t, "中国/ns 人口/n ", SegmentsToString(segments, false))
+
+	output := ""
+	for _, info := range infos {
+		output += fmt.Sprintf("%d:%s:%g ", info.Position, info.TokenText, info.MinDistance)
+	}
+	expect(t, "0:中:32 1:中国:2 2:国人:33 3:人口:4 ", output)
+
+	segments, infos = seg.SegmentDebug(nil)
+	expect(t, "0", len(segments)+len(infos))
+}
@@ -0,0 +1,112 @@
+package sego
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 从语料统计词频生成词典
+//
+// 用法：
+//
+//	builder := NewDictBuilder(4)
+//	for _, text := range corpus {
+//		builder.AddText(text)
+//	}
+//	seg.LoadDictionary(builder.Build(5))
+//
+// DictBuilder不能被多个goroutine同时使用。
+type DictBuilder struct {
+	// 统计的分词最多包含的字元数
+	maxLength int
+
+	// 每个候选分词的出现次数
+	counts map[string]int
+}
+
+// 创建统计最多maxLength个字元的分词的词典生成器，maxLength小于1时按1处理
+func NewDictBuilder(maxLength int) *DictBuilder {
+	return &DictBuilder{maxLength: maxInt(maxLength, 1), counts: make(map[string]int)}
+}
+
+// 统计未分词文本中的n元组
+//
+// 文本按和分词相同的规则划分成字元（英文单词和数字串各是一个字元，英文转为
+// 小写），在空白和标点处断开。每段中长度从1到maxLength个字元的所有n元组都
+// 计数一次；英文单词和数字串只单独计数，不和相邻的字元组成n元组，因为它们
+// 拼接后在分词时不再是原来的字元。
+func (builder *DictBuilder) AddText(text string) {
+	var run []Text
+	for _, word := range splitTextToWords([]byte(text)) {
+		if !isWordText(word) {
+			builder.addRun(run)
+			run = run[:0]
+			continue
+		}
+		if r, size := utf8.DecodeRune(word); size <= 2 && (unicode.IsLetter(r) || unicode.IsNumber(r)) {
+			// 英文单词和数字串
+			builder.addRun(run)
+			run = run[:0]
+			builder.counts[string(word)]++
+			continue
+		}
+		run = append(run, word)
+	}
+	builder.addRun(run)
+}
+
+// 统计已经分好词的文本，词之间用空白分隔
+//
+// 每个词计数一次，超过maxLength个字元的词和全部由标点组成的词被忽略。
+func (builder *DictBuilder) AddSegmented(text string) {
+	for _, word := range strings.Fields(text) {
+		words := splitTextToWords([]byte(word))
+		if len(words) == 0 || len(words) > builder.maxLength || isPunctText(word) {
+			continue
+		}
+		builder.counts[string(textSliceToBytes(words))]++
+	}
+}
+
+// 统计一段连续的中日韩字元中的所有n元组
+func (builder *DictBuilder) addRun(run []Text) {
+	for start := range run {
+		for end := start + 1; end <= len(run) && end-start <= builder.maxLength; end++ {
+			builder.counts[string(textSliceToBytes(run[start:end]))]++
+		}
+	}
+}
+
+// 返回出现次数不少于minFreq的分词组成的词典内容，可以直接用LoadDictionary载入
+//
+// 每行的格式为"分词文本 词频"，词性留空，按词频从高到低排列，词频相同时按文本
+// 的字典序。注意LoadDictionary忽略词频小于2的分词。
+func (builder *DictBuilder) Build(minFreq int) string {
+	words := make([]string, 0, len(builder.counts))
+	for word, count := range builder.counts {
+		if count >= minFreq {
+			words = append(words, word)
+		}
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if builder.counts[words[i]] != builder.counts[words[j]] {
+			return builder.counts[words[i]] > builder.counts[words[j]]
+		}
+		return words[i] < words[j]
+	})
+
+	var output strings.Builder
+	for _, word := range words {
+		fmt.Fprintf(&output, "%s %d\n", word, builder.counts[word])
+	}
+	return output.String()
+}
+
+// 判断字元是否由字母或数字组成（不是空白、标点或符号）
+func isWordText(word Text) bool {
+	r, _ := utf8.DecodeRune(word)
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsNumber(r))
+}
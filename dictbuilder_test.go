@@ -0,0 +1,23 @@
+package sego
+
+import (
+	"testing"
+)
+
+func TestDictBuilder(t *testing.T) {
+	builder := NewDictBuilder(2)
+	builder.AddText("中国人，中国。GitHub中国")
+	builder.AddText("中国 github")
+	expect(t, "中 4\n中国 4\n国 4\ngithub 2\n人 1\n国人 1\n", builder.Build(1))
+	expect(t, "中 4\n中国 4\n国 4\ngithub 2\n", builder.Build(2))
+
+	// 生成的词典可以直接载入
+	var seg Segmenter
+	seg.LoadDictionary(builder.Build(2))
+	expect(t, "中国/ 人/x ", SegmentsToString(seg.Segment([]byte("中国人")), false))
+
+	segmented := NewDictBuilder(3)
+	segmented.AddSegmented("中华人民共和国 成立 了 ， 成立 大会")
+	segmented.AddSegmented("大会 成立 了")
+	expect(t, "成立 3\n了 2\n大会 2\n", segmented.Build(2))
+}
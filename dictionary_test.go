@@ -0,0 +1,26 @@
+package sego
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDictionaryChecksum(t *testing.T) {
+	var a, b, c Segmenter
+	a.LoadDictionary("中国 10 ns\n人口 20 n\nGitHub 5 nz\n")
+	b.LoadDictionary("github 5 nz\n人口 20 n\n中国 10 ns\n")
+	c.LoadDictionary("中国 10 ns\n人口 20 v\nGitHub 5 nz\n")
+
+	expect(t, fmt.Sprintf("%x", a.dict.Checksum()), fmt.Sprintf("%x", b.dict.Checksum()))
+	expect(t, "false", a.dict.Checksum() == c.dict.Checksum())
+
+	// 校验和不依赖于Go的版本
+	expect(t, "e7986c6b1dc99cbc045e4b2286b6a6465d7b39be4ac733490053c0330b83e4bd",
+		fmt.Sprintf("%x", a.dict.Checksum()))
+	var empty Segmenter
+	empty.LoadDictionary("")
+	expect(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		fmt.Sprintf("%x", empty.dict.Checksum()))
+	b.AddToken("十三亿", 10, "m")
+	expect(t, "false", a.dict.Checksum() == b.dict.Checksum())
+}
@@ -0,0 +1,87 @@
+package sego
+
+import (
+	"bufio"
+	"sort"
+	"strings"
+)
+
+// 两个词典之间的差异，见DiffDictionaries
+type DictDiff struct {
+	Added            []DictDiffEntry       // 只在新词典中的分词
+	Removed          []DictDiffEntry       // 只在旧词典中的分词
+	FrequencyChanged []DictFrequencyChange // 两个词典中都有但词频不同的分词
+}
+
+// 只在一个词典中出现的分词
+type DictDiffEntry struct {
+	Text      string
+	Frequency int
+	POS       string
+}
+
+// 词频发生变化的分词
+type DictFrequencyChange struct {
+	Text         string
+	OldFrequency int
+	NewFrequency int
+}
+
+// 比较两个词典的内容，返回新增、删除和词频变化的分词
+//
+// 两个词典按照和Segmenter.LoadDictionary相同的规则解析，载入时会被忽略的行
+// 不参与比较。分词按文本比较，英文分词按小写比较，重复的分词以第一次出现的
+// 为准，和载入时一致。结果中的分词文本为载入后的文本（英文为小写），每个列表
+// 都按文本排序。
+func DiffDictionaries(oldContent, newContent string) DictDiff {
+	oldEntries := parseDictionaryEntries(oldContent)
+	newEntries := parseDictionaryEntries(newContent)
+
+	diff := DictDiff{
+		Added:            []DictDiffEntry{},
+		Removed:          []DictDiffEntry{},
+		FrequencyChanged: []DictFrequencyChange{},
+	}
+	for text, entry := range newEntries {
+		old, ok := oldEntries[text]
+		if !ok {
+			diff.Added = append(diff.Added, DictDiffEntry{text, entry.frequency, entry.pos})
+		} else if old.frequency != entry.frequency {
+			diff.FrequencyChanged = append(diff.FrequencyChanged,
+				DictFrequencyChange{text, old.frequency, entry.frequency})
+		}
+	}
+	for text, entry := range oldEntries {
+		if _, ok := newEntries[text]; !ok {
+			diff.Removed = append(diff.Removed, DictDiffEntry{text, entry.frequency, entry.pos})
+		}
+	}
+
+	sort.Slice(diff.Added, func(i, j int) bool { return diff.Added[i].Text < diff.Added[j].Text })
+	sort.Slice(diff.Removed, func(i, j int) bool { return diff.Removed[i].Text < diff.Removed[j].Text })
+	sort.Slice(diff.FrequencyChanged, func(i, j int) bool {
+		return diff.FrequencyChanged[i].Text < diff.FrequencyChanged[j].Text
+	})
+	return diff
+}
+
+// 解析词典内容，返回载入后的分词文本到词典条目的映射
+func parseDictionaryEntries(content string) map[string]dictEntry {
+	entries := make(map[string]dictEntry)
+	reader := bufio.NewReader(strings.NewReader(strings.TrimPrefix(content, utf8BOM)))
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && len(line) == 0 {
+			break
+		}
+		entry, issue := parseDictionaryLine(line)
+		if issue != dictLineOK {
+			continue
+		}
+		text := string(textSliceToBytes(splitTextToWords([]byte(entry.text))))
+		if _, ok := entries[text]; !ok {
+			entries[text] = entry
+		}
+	}
+	return entries
+}
@@ -0,0 +1,22 @@
+package sego
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDiffDictionaries(t *testing.T) {
+	oldContent := "中国 10 ns\n人民 10 n\nGitHub 5 nz\n万岁 3 i\n# 注释\n"
+	newContent := "\uFEFF人民 12 n\n中国 10 ns\ngithub 5 nz\n共和国 8 ns\n中国 20 ns\n错误\n低频 1 n\n"
+
+	diff := DiffDictionaries(oldContent, newContent)
+	expect(t, "[{共和国 8 ns}]", fmt.Sprint(diff.Added))
+	expect(t, "[{万岁 3 i}]", fmt.Sprint(diff.Removed))
+	expect(t, "[{人民 10 12}]", fmt.Sprint(diff.FrequencyChanged))
+
+	diff = DiffDictionaries(oldContent, oldContent)
+	expect(t, "0 0 0", fmt.Sprint(len(diff.Added), len(diff.Removed), len(diff.FrequencyChanged)))
+
+	diff = DiffDictionaries("", "b 3\na 3\nc 3\n")
+	expect(t, "[{a 3 } {b 3 } {c 3 }]", fmt.Sprint(diff.Added))
+}
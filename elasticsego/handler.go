@@ -0,0 +1,124 @@
+// 以Elasticsearch分析接口（_analyze）的格式提供sego分词的HTTP服务
+//
+// 服务接收{"text":"..."}格式的JSON请求，返回和Elasticsearch自定义分析器相同
+// 格式的分词流，可以作为Elasticsearch的外部分词服务使用：
+//
+//	var segmenter sego.Segmenter
+//	segmenter.LoadDictionary("dictionary.txt")
+//	http.Handle("/_analyze", elasticsego.NewHandler(&segmenter))
+package elasticsego
+
+import (
+	"encoding/json"
+	"net/http"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/Aopro7/sego"
+)
+
+// 分析请求
+type Request struct {
+	Text string `json:"text"`
+}
+
+// 分析结果中的一个分词
+type Token struct {
+	Token       string `json:"token"`
+	StartOffset int    `json:"start_offset"`
+	EndOffset   int    `json:"end_offset"`
+	Type        string `json:"type"`
+	Position    int    `json:"position"`
+}
+
+// 分析结果
+type Response struct {
+	Tokens []Token `json:"tokens"`
+}
+
+// 请求出错时返回的结果
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+type handler struct {
+	segmenter *sego.Segmenter
+}
+
+// 创建使用分词器seg的HTTP处理器，seg应已载入词典
+//
+// 请求体是JSON格式的Request，返回JSON格式的Response，请求体无法解析时返回400
+// 和{"error":"..."}。
+func NewHandler(seg *sego.Segmenter) http.Handler {
+	return &handler{segmenter: seg}
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var request Request
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(&errorResponse{Error: err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(Analyze(h.segmenter, request.Text))
+}
+
+// 对text分词，返回Elasticsearch格式的分析结果
+//
+// 和Elasticsearch一样，偏移量以UTF-16编码单元计（基本多文种平面内的字符
+// 就是字符数），位置从0开始。空白和标点组成的分词被跳过，不占用位置。分词的
+// 文本是sego规范化后的文本（英文为小写）。
+func Analyze(seg *sego.Segmenter, text string) *Response {
+	input := []byte(text)
+	response := &Response{Tokens: []Token{}}
+
+	// 字节位置到UTF-16偏移量的转换随分词依次向后推进
+	bytePosition, offset := 0, 0
+	toOffset := func(position int) int {
+		for bytePosition < position {
+			r, size := utf8.DecodeRune(input[bytePosition:])
+			offset += utf16Length(r)
+			bytePosition += size
+		}
+		return offset
+	}
+
+	for _, segment := range seg.Segment(input) {
+		start := toOffset(segment.Start())
+		end := toOffset(segment.End())
+		if isSeparator(segment.Bytes(input)) {
+			continue
+		}
+		response.Tokens = append(response.Tokens, Token{
+			Token:       segment.Token().Text(),
+			StartOffset: start,
+			EndOffset:   end,
+			Type:        "word",
+			Position:    len(response.Tokens),
+		})
+	}
+	return response
+}
+
+// 返回字符的UTF-16编码单元数，非法字节按一个单元计
+func utf16Length(r rune) int {
+	if utf16.IsSurrogate(r) || r < 0x10000 {
+		return 1
+	}
+	return 2
+}
+
+// 判断分词是否全部由空白和标点组成
+func isSeparator(term []byte) bool {
+	for current := 0; current < len(term); {
+		r, size := utf8.DecodeRune(term[current:])
+		if !unicode.IsSpace(r) && !unicode.IsPunct(r) {
+			return false
+		}
+		current += size
+	}
+	return true
+}
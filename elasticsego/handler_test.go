@@ -0,0 +1,48 @@
+package elasticsego
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Aopro7/sego"
+)
+
+func TestHandler(t *testing.T) {
+	var seg sego.Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\ngithub 10 nz\n")
+	handler := NewHandler(&seg)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("POST", "/_analyze",
+		strings.NewReader(`{"text":"中国人口，GitHub 😀"}`)))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("期待状态码200，实际%d", recorder.Code)
+	}
+	expected := `{"tokens":[` +
+		`{"token":"中国","start_offset":0,"end_offset":2,"type":"word","position":0},` +
+		`{"token":"人口","start_offset":2,"end_offset":4,"type":"word","position":1},` +
+		`{"token":"github","start_offset":5,"end_offset":11,"type":"word","position":2},` +
+		`{"token":"😀","start_offset":12,"end_offset":14,"type":"word","position":3}]}` + "\n"
+	if recorder.Body.String() != expected {
+		t.Errorf("期待值=%s, 实际=%s", expected, recorder.Body.String())
+	}
+
+	// 空文本返回空的分词列表
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("POST", "/_analyze", strings.NewReader(`{"text":""}`)))
+	if recorder.Body.String() != `{"tokens":[]}`+"\n" {
+		t.Errorf("空文本的结果错误：%s", recorder.Body.String())
+	}
+
+	// 无法解析的请求
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("POST", "/_analyze", strings.NewReader(`{"text":`)))
+	var response errorResponse
+	if recorder.Code != http.StatusBadRequest || json.Unmarshal(recorder.Body.Bytes(), &response) != nil ||
+		response.Error == "" {
+		t.Errorf("期待400和错误信息，实际%d %s", recorder.Code, recorder.Body.String())
+	}
+}
@@ -0,0 +1,105 @@
+package sego
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"golang.org/x/text/transform"
+)
+
+// 非UTF8编码的文本转换为UTF8时，对无法转换的字节的处理方式
+type DecodeErrorMode int
+
+const (
+	// 将无法转换的字节替换为U+FFFD，这是默认的处理方式
+	DecodeErrorReplace DecodeErrorMode = iota
+
+	// 丢弃无法转换的字节
+	DecodeErrorSkip
+
+	// 遇到无法转换的字节时不分词，返回错误
+	DecodeErrorStrict
+)
+
+// 设置非UTF8编码输入中无法转换的字节的处理方式，见SegmentGBK和SegmentBig5
+func WithDecodeErrorMode(mode DecodeErrorMode) Option {
+	return func(seg *Segmenter) {
+		seg.decodeErrorMode = mode
+	}
+}
+
+// 对GBK（兼容GB2312）编码的文本分词
+//
+// 文本先被转换为UTF8再分词，分词的Start和End是相对于输入text的字节位置，
+// 可以直接用来截取text；RuneStart和RuneEnd按字符计数。分词的文本（见
+// Token.Text）是UTF8编码的。无法转换的字节按WithDecodeErrorMode设置的方式处理。
+func (seg *Segmenter) SegmentGBK(text []byte) ([]Segment, error) {
+	return seg.segmentEncoded(text, simplifiedchinese.GBK, "GBK")
+}
+
+// 对Big5编码的文本分词，处理方式同SegmentGBK
+func (seg *Segmenter) SegmentBig5(text []byte) ([]Segment, error) {
+	return seg.segmentEncoded(text, traditionalchinese.Big5, "Big5")
+}
+
+func (seg *Segmenter) segmentEncoded(
+	text []byte, enc encoding.Encoding, name string) ([]Segment, error) {
+	decoded, spans, err := decodeText(text, enc, seg.decodeErrorMode)
+	if err != nil {
+		return nil, fmt.Errorf("sego: %s转换UTF8失败: %w", name, err)
+	}
+
+	// 将相对于UTF8文本的字节位置映射回原文本，字符位置两者相同
+	segments := seg.internalSegment(decoded, false)
+	for i := range segments {
+		segments[i].start = spans[segments[i].start].start
+		segments[i].end = spans[segments[i].end-1].end
+	}
+	return segments, nil
+}
+
+// 将文本从enc编码转换为UTF8，同时返回转换结果中每个字节在text中对应的字节区间
+//
+// 每次只转换一个字符：dst从一个字节开始逐渐加长，能放下第一个字符时一定放不下
+// 第二个，因此nSrc就是该字符在text中的长度。x/text的解码器把无法转换的字节
+// 替换为U+FFFD，GBK和Big5都不包含该字符，所以转换出的U+FFFD都来自无法转换的
+// 字节。
+func decodeText(text []byte, enc encoding.Encoding, mode DecodeErrorMode) (
+	[]byte, []textSpan, error) {
+	decoder := enc.NewDecoder()
+	decoded := make([]byte, 0, len(text)*3/2)
+	spans := make([]textSpan, 0, cap(decoded))
+	var buf [utf8.UTFMax]byte
+	for current := 0; current < len(text); {
+		nDst, nSrc := 0, 0
+		for size := 1; nDst == 0 && size <= len(buf); size++ {
+			var err error
+			nDst, nSrc, err = decoder.Transform(buf[:size], text[current:], true)
+			if err != nil && err != transform.ErrShortDst {
+				return nil, nil, err
+			}
+		}
+		if nDst == 0 {
+			return nil, nil, fmt.Errorf("%w（字节位置%d）", ErrInvalidEncoding, current)
+		}
+
+		if r, _ := utf8.DecodeRune(buf[:nDst]); r == utf8.RuneError {
+			switch mode {
+			case DecodeErrorSkip:
+				current += nSrc
+				continue
+			case DecodeErrorStrict:
+				return nil, nil, fmt.Errorf("%w（字节位置%d）", ErrInvalidEncoding, current)
+			}
+		}
+		for i := 0; i < nDst; i++ {
+			spans = append(spans, textSpan{start: current, end: current + nSrc})
+		}
+		decoded = append(decoded, buf[:nDst]...)
+		current += nSrc
+	}
+	return decoded, spans, nil
+}
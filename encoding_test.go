@@ -0,0 +1,80 @@
+package sego
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+func TestSegmentGBK(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n")
+
+	// "中国人口"的GBK编码
+	segments, err := seg.SegmentGBK([]byte("\xd6\xd0\xb9\xfa\xc8\xcb\xbf\xda"))
+	expect(t, "<nil>", err)
+	expect(t, "中国/ns 人口/n ", SegmentsToString(segments, false))
+	expect(t, "4", segments[1].start)
+	expect(t, "8", segments[1].end)
+	expect(t, "2", segments[1].runeStart)
+	expect(t, "4", segments[1].runeEnd)
+
+	// 无法转换的字节默认替换为U+FFFD
+	text := []byte("\xd6\xd0\xb9\xfa\xff\xc8\xcb\xbf\xda")
+	segments, err = seg.SegmentGBK(text)
+	expect(t, "<nil>", err)
+	expect(t, "中国/ns �/x 人口/n ", SegmentsToString(segments, false))
+
+	skipSeg := NewSegmenter(WithDecodeErrorMode(DecodeErrorSkip))
+	skipSeg.LoadDictionary("中国 10 ns\n人口 10 n\n")
+	segments, err = skipSeg.SegmentGBK(text)
+	expect(t, "<nil>", err)
+	expect(t, "中国/ns 人口/n ", SegmentsToString(segments, false))
+	expect(t, "5", segments[1].start)
+	expect(t, "9", segments[1].end)
+
+	strictSeg := NewSegmenter(WithDecodeErrorMode(DecodeErrorStrict))
+	strictSeg.LoadDictionary("中国 10 ns\n人口 10 n\n")
+	segments, err = strictSeg.SegmentGBK(text)
+	expect(t, "true", errors.Is(err, ErrInvalidEncoding))
+	expect(t, "0", len(segments))
+}
+
+func TestSegmentBig5(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中國 10 ns\n人口 10 n\n")
+
+	// "中國人口"的Big5编码
+	segments, err := seg.SegmentBig5([]byte("\xa4\xa4\xb0\xea\xa4\x48\xa4\x66"))
+	expect(t, "<nil>", err)
+	expect(t, "中國/ns 人口/n ", SegmentsToString(segments, false))
+}
+
+func TestSegmentGBKOffsets(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n")
+
+	// "GDP和中国人口"的GBK编码，中间有一个无法转换的字节
+	text := []byte("GDP\xba\xcd\xd6\xd0\xb9\xfa\xff\xc8\xcb\xbf\xda")
+	segments, err := seg.SegmentGBK(text)
+	expect(t, "<nil>", err)
+	expect(t, "gdp/x 和/x 中国/ns �/x 人口/n ", SegmentsToString(segments, false))
+	decoder := simplifiedchinese.GBK.NewDecoder()
+	var words []string
+	for _, segment := range segments {
+		word, err := decoder.Bytes(text[segment.Start():segment.End()])
+		expect(t, "<nil>", err)
+		words = append(words, string(word))
+	}
+	expect(t, "GDP 和 中国 � 人口", strings.Join(words, " "))
+	expect(t, "6", segments[3].RuneStart())
+	expect(t, "7", segments[3].RuneEnd())
+
+	// "中國人口"的Big5编码
+	segments, err = seg.SegmentBig5([]byte("\xa4\xa4\xb0\xea\xa4\x48\xa4\x66"))
+	expect(t, "<nil>", err)
+	expect(t, "2", segments[1].Start())
+	expect(t, "4", segments[1].End())
+}
@@ -0,0 +1,262 @@
+package sego
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+)
+
+// 分词结果的过滤器，接收一组分词，返回处理后的分词
+//
+// 过滤器不应修改输入的切片和其中分词的Token（Token可能属于共享的词典），
+// 需要改变分词时返回新的切片和新的Token。
+type TokenFilter interface {
+	Filter(segs []Segment) []Segment
+}
+
+// 将普通函数用作TokenFilter
+type TokenFilterFunc func(segs []Segment) []Segment
+
+// 调用f(segs)
+func (f TokenFilterFunc) Filter(segs []Segment) []Segment {
+	return f(segs)
+}
+
+// 按顺序依次应用的一组过滤器，零值是不做任何处理的空过滤链
+//
+// 比如：
+//
+//	chain := NewFilterChain().Add(LowercaseFilter()).Add(MinLengthFilter(2))
+//	segments := chain.Apply(seg.Segment(text))
+type FilterChain struct {
+	filters []TokenFilter
+}
+
+// 创建包含filters的过滤链
+func NewFilterChain(filters ...TokenFilter) *FilterChain {
+	return &FilterChain{filters: append([]TokenFilter(nil), filters...)}
+}
+
+// 在过滤链末尾加入一个过滤器，返回过滤链本身以便链式调用
+func (chain *FilterChain) Add(filter TokenFilter) *FilterChain {
+	chain.filters = append(chain.filters, filter)
+	return chain
+}
+
+// 依次用过滤链中的过滤器处理segs，返回最后的结果
+func (chain *FilterChain) Apply(segs []Segment) []Segment {
+	for _, filter := range chain.filters {
+		segs = filter.Filter(segs)
+	}
+	return segs
+}
+
+// 返回将分词文本转换为小写的过滤器
+//
+// 分词时英文字母已经转换为小写，该过滤器还处理其他有大小写的文字（比如希腊
+// 字母和西里尔字母）。文本有变化的分词使用新的Token，位置不变。
+func LowercaseFilter() TokenFilter {
+	return TokenFilterFunc(func(segs []Segment) []Segment {
+		output := make([]Segment, len(segs))
+		for i, s := range segs {
+			output[i] = s
+			text := s.token.Text()
+			if lower := strings.ToLower(text); lower != text {
+				token := &Token{frequency: s.token.frequency, distance: s.token.distance,
+					pos: s.token.pos, posTags: s.token.posTags, kind: s.token.kind}
+				for _, word := range s.token.text {
+					token.text = append(token.text, bytes.ToLower(word))
+				}
+				output[i].token = token
+			}
+		}
+		return output
+	})
+}
+
+// 返回去掉停用词的过滤器，停用词不区分英文大小写
+//
+// 停用词被去掉后仍然占用位置：其后第一个保留的分词的位置增量（见
+// Segment.PositionIncrement）加上去掉的停用词数，末尾的停用词不影响结果。
+func StopWordFilter(words ...string) TokenFilter {
+	stopWords := make(map[string]bool, len(words))
+	for _, word := range words {
+		stopWords[strings.ToLower(word)] = true
+	}
+	return TokenFilterFunc(func(segs []Segment) []Segment {
+		output := make([]Segment, 0, len(segs))
+		removed := 0
+		for _, s := range segs {
+			if stopWords[strings.ToLower(s.token.Text())] {
+				removed += s.PositionIncrement()
+				continue
+			}
+			s.positionGap += removed
+			removed = 0
+			output = append(output, s)
+		}
+		return output
+	})
+}
+
+// 返回去掉字符数少于n的分词的过滤器
+func MinLengthFilter(n int) TokenFilter {
+	return keepFilter(func(s *Segment) bool {
+		return utf8.RuneCountInString(s.token.Text()) >= n
+	})
+}
+
+// 返回去掉字符数多于n的分词的过滤器
+func MaxLengthFilter(n int) TokenFilter {
+	return keepFilter(func(s *Segment) bool {
+		return utf8.RuneCountInString(s.token.Text()) <= n
+	})
+}
+
+// 返回去掉重复分词的过滤器，文本相同的分词只保留第一个
+func DeduplicateFilter() TokenFilter {
+	return TokenFilterFunc(func(segs []Segment) []Segment {
+		seen := make(map[string]bool, len(segs))
+		output := make([]Segment, 0, len(segs))
+		for _, s := range segs {
+			text := s.token.Text()
+			if !seen[text] {
+				seen[text] = true
+				output = append(output, s)
+			}
+		}
+		return output
+	})
+}
+
+// 返回只保留keep返回true的分词的过滤器
+func keepFilter(keep func(s *Segment) bool) TokenFilter {
+	return TokenFilterFunc(func(segs []Segment) []Segment {
+		output := make([]Segment, 0, len(segs))
+		for i := range segs {
+			if keep(&segs[i]) {
+				output = append(output, segs[i])
+			}
+		}
+		return output
+	})
+}
+
+// 返回在每个分词之后追加分词内n个字符的n元组的过滤器，用于提高部分匹配的召回率
+//
+// 比如n为1时"中国"之后追加"中"和"国"。只有字符数多于n的分词才追加n元组。
+// n元组的位置是在原文本中的位置（分词文本和原文本长度不一致时，比如经过词干
+// 提取，使用整个分词的位置），词性为"x"，类别为KindPseudo。n小于1时不追加。
+func NGramFilter(n int) TokenFilter {
+	return TokenFilterFunc(func(segs []Segment) []Segment {
+		output := make([]Segment, 0, len(segs))
+		for _, s := range segs {
+			output = append(output, s)
+			if n >= 1 {
+				output = appendNGrams(output, s, n)
+			}
+		}
+		return output
+	})
+}
+
+// 返回在每个分词的位置输出其长度从minGram到maxGram个字符的所有前缀的过滤器，
+// 用于支持前缀查询
+//
+// 比如minGram为1、maxGram为2时"中国"输出"中"和"中国"，"中华人民共和国"输出
+// "中"和"中华"。和Elasticsearch的edge_ngram一样，原分词本身不保留（除非它
+// 的长度在范围内，这时输出原分词），字符数少于minGram的分词被去掉。前缀的
+// 位置、词性和类别的规则同NGramFilter。minGram小于1时按1处理。
+func EdgeNGramFilter(minGram, maxGram int) TokenFilter {
+	minGram = maxInt(minGram, 1)
+	return TokenFilterFunc(func(segs []Segment) []Segment {
+		output := make([]Segment, 0, len(segs))
+		for _, s := range segs {
+			chars := segmentChars(s)
+			for length := minGram; length <= maxGram && length <= len(chars); length++ {
+				if length == len(chars) {
+					output = append(output, s)
+				} else {
+					output = append(output, charGram(s, chars, 0, length))
+				}
+			}
+		}
+		return output
+	})
+}
+
+// 将分词s中的所有n元组追加到output
+func appendNGrams(output []Segment, s Segment, n int) []Segment {
+	chars := segmentChars(s)
+	if len(chars) <= n {
+		return output
+	}
+	for i := 0; i+n <= len(chars); i++ {
+		output = append(output, charGram(s, chars, i, n))
+	}
+	return output
+}
+
+// 将分词文本按字符划分
+func segmentChars(s Segment) []Text {
+	text := []byte(s.token.Text())
+	chars := make([]Text, 0, len(text))
+	for current := 0; current < len(text); {
+		_, size := utf8.DecodeRune(text[current:])
+		chars = append(chars, text[current:current+size:current+size])
+		current += size
+	}
+	return chars
+}
+
+// 返回分词s中从第from个字符开始的length个字符组成的伪分词，chars是s的字符
+//
+// 分词文本和原文本长度一致时位置是这些字符在原文本中的位置，否则是整个分词
+// 的位置。
+func charGram(s Segment, chars []Text, from, length int) Segment {
+	gram := Segment{start: s.start, end: s.end, runeStart: s.runeStart, runeEnd: s.runeEnd,
+		token: &Token{text: chars[from : from+length : from+length], frequency: 1, pos: "x", kind: KindPseudo}}
+	if textSliceByteLength(chars) == s.end-s.start {
+		gram.start = s.start + textSliceByteLength(chars[:from])
+		gram.end = gram.start + textSliceByteLength(gram.token.text)
+		gram.runeStart = s.runeStart + from
+		gram.runeEnd = gram.runeStart + length
+	}
+	return gram
+}
+
+// 返回在每个分词之后追加从它开始的minShingle到maxShingle个相邻分词拼接成的
+// 分词（shingle）的过滤器，用于提高短语匹配的精度
+//
+// 比如"中国 经济 发展"在minShingle和maxShingle都为2时输出"中国"、"中国经济"、
+// "经济"、"经济发展"、"发展"。原分词都保留，拼接不跨越空白和标点，少于两个
+// 分词的拼接没有意义，minShingle小于2时按2处理。拼接的分词位置覆盖所有原分词，
+// 词性为"x"，类别为KindPseudo，原分词可以用Segment.Components取得。
+func ShingleFilter(minShingle, maxShingle int) TokenFilter {
+	minShingle = maxInt(minShingle, 2)
+	return TokenFilterFunc(func(segs []Segment) []Segment {
+		output := make([]Segment, 0, len(segs))
+		for i, s := range segs {
+			output = append(output, s)
+			if isShingleBreak(&s) {
+				continue
+			}
+			for size := 2; size <= maxShingle && i+size <= len(segs); size++ {
+				if isShingleBreak(&segs[i+size-1]) {
+					break
+				}
+				if size >= minShingle {
+					output = append(output, mergeSegments(segs[i:i+size],
+						&Token{frequency: 1, pos: "x", kind: KindPseudo}))
+				}
+			}
+		}
+		return output
+	})
+}
+
+// 判断分词是否是拼接不能跨越的空白或标点
+func isShingleBreak(s *Segment) bool {
+	text := s.token.Text()
+	return strings.TrimSpace(text) == "" || isPunctText(text)
+}
@@ -0,0 +1,114 @@
+package sego
+
+import (
+	"testing"
+)
+
+func TestFilterChain(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n的 10 uj\n人口 10 n\n中华人民共和国 10 ns\n")
+	segs := seg.Segment([]byte("中国的人口，ΑΘΗΝΑ的人口和中华人民共和国"))
+
+	// 空过滤链原样返回
+	var empty FilterChain
+	expect(t, SegmentsToString(segs, false), SegmentsToString(empty.Apply(segs), false))
+
+	chain := NewFilterChain(LowercaseFilter()).
+		Add(StopWordFilter("的", "和", "，")).
+		Add(DeduplicateFilter())
+	filtered := chain.Apply(segs)
+	expect(t, "中国/ns 人口/n αθηνα/x 中华人民共和国/ns ", SegmentsToString(filtered, false))
+	expect(t, "18", filtered[2].Start())
+	expect(t, "28", filtered[2].End())
+	// 不改变输入
+	expect(t, "ΑΘΗΝΑ", segs[4].Token().Text())
+
+	expect(t, "中国/ns 人口/n αθηνα/x ",
+		SegmentsToString(chain.Add(MaxLengthFilter(5)).Apply(segs), false))
+	expect(t, "αθηνα/x 中华人民共和国/ns ",
+		SegmentsToString(NewFilterChain(LowercaseFilter(), DeduplicateFilter(), MinLengthFilter(3)).Apply(segs), false))
+
+	// 自定义过滤器
+	reverse := TokenFilterFunc(func(segs []Segment) []Segment {
+		output := make([]Segment, len(segs))
+		for i, s := range segs {
+			output[len(segs)-1-i] = s
+		}
+		return output
+	})
+	expect(t, "人口/n 的/uj 中国/ns ", SegmentsToString(NewFilterChain(reverse).Apply(segs[:3]), false))
+}
+
+func TestStopWordFilterPositionIncrement(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n的 10 uj\n人口 10 n\n")
+	segs := seg.Segment([]byte("的中国的的人口的"))
+	expect(t, "1", segs[1].PositionIncrement())
+
+	filtered := StopWordFilter("的").Filter(segs)
+	expect(t, "中国/ns 人口/n ", SegmentsToString(filtered, false))
+	expect(t, "2", filtered[0].PositionIncrement())
+	expect(t, "3", filtered[1].PositionIncrement())
+
+	// 多个停用词过滤器的位置增量累加
+	filtered = NewFilterChain(StopWordFilter("的"), StopWordFilter("中国")).Apply(segs)
+	expect(t, "人口/n ", SegmentsToString(filtered, false))
+	expect(t, "5", filtered[0].PositionIncrement())
+}
+
+func TestNGramFilter(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n中华人民 10 nz\n")
+	segs := seg.Segment([]byte("GitHub在中国"))
+
+	filtered := NGramFilter(1).Filter(segs)
+	expect(t, "github/x g/x i/x t/x h/x u/x b/x 在/x 中国/ns 中/x 国/x ", SegmentsToString(filtered, false))
+	expect(t, "12", filtered[10].Start())
+	expect(t, "15", filtered[10].End())
+	expect(t, "8", filtered[10].RuneStart())
+	expect(t, "9", filtered[10].RuneEnd())
+
+	filtered = NGramFilter(2).Filter(seg.Segment([]byte("中华人民")))
+	expect(t, "中华人民/nz 中华/x 华人/x 人民/x ", SegmentsToString(filtered, false))
+	expect(t, "3", filtered[2].Start())
+	expect(t, "9", filtered[2].End())
+
+	// 字符数不多于n的分词和n小于1时不追加
+	expect(t, "github/x 在/x 中国/ns ", SegmentsToString(NGramFilter(6).Filter(segs), false))
+	expect(t, "github/x 在/x 中国/ns ", SegmentsToString(NGramFilter(0).Filter(segs), false))
+}
+
+func TestEdgeNGramFilter(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n中华人民共和国 10 ns\n")
+	segs := seg.Segment([]byte("中国和中华人民共和国"))
+
+	filtered := EdgeNGramFilter(1, 2).Filter(segs)
+	expect(t, "中/x 中国/ns 和/x 中/x 中华/x ", SegmentsToString(filtered, false))
+	expect(t, "9", filtered[3].Start())
+	expect(t, "15", filtered[4].End())
+	expect(t, "3", filtered[4].RuneStart())
+	expect(t, "5", filtered[4].RuneEnd())
+
+	// 字符数少于minGram的分词被去掉
+	expect(t, "中国/ns 中华/x 中华人/x ", SegmentsToString(EdgeNGramFilter(2, 3).Filter(segs), false))
+	expect(t, "", SegmentsToString(EdgeNGramFilter(3, 2).Filter(segs), false))
+}
+
+func TestShingleFilter(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n经济 10 n\n发展 10 vn\n迅速 10 ad\n")
+	segs := seg.Segment([]byte("中国经济发展，迅速"))
+
+	filtered := ShingleFilter(2, 2).Filter(segs)
+	expect(t, "中国/ns 中国经济/x 经济/n 经济发展/x 发展/vn ，/w 迅速/ad ", SegmentsToString(filtered, false))
+	expect(t, "0", filtered[1].Start())
+	expect(t, "12", filtered[1].End())
+	expect(t, "中国/ns 经济/n ", SegmentsToString(filtered[1].Components(), false))
+
+	expect(t, "中国/ns 中国经济/x 中国经济发展/x 经济/n 经济发展/x 发展/vn ，/w 迅速/ad ",
+		SegmentsToString(ShingleFilter(1, 3).Filter(segs), false))
+	expect(t, "中国/ns 中国经济发展/x 经济/n 发展/vn ，/w 迅速/ad ",
+		SegmentsToString(ShingleFilter(3, 3).Filter(segs), false))
+	expect(t, SegmentsToString(segs, false), SegmentsToString(ShingleFilter(2, 1).Filter(segs), false))
+}
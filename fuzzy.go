@@ -0,0 +1,130 @@
+package sego
+
+import (
+	"sort"
+)
+
+// 模糊查找的结果
+type FuzzyMatch struct {
+	Token    *Token // 词典中的分词
+	Distance int    // 和查询词之间以字符计的编辑距离
+}
+
+// BK树的节点，按编辑距离组织词典中的分词
+type bkNode struct {
+	token    int             // 分词在Dictionary.tokens中的下标
+	runes    []rune          // 分词的文本
+	children map[int]*bkNode // 以到本节点的编辑距离为键的子树
+}
+
+// 在词典中查找和word的编辑距离（Levenshtein距离，按字符计算）不超过maxDist的分词
+//
+// 结果按编辑距离从小到大排序，距离相同时按分词在词典中的顺序排列。英文不区分
+// 大小写。maxDist为1时可以用于拼写和OCR纠错。
+//
+// 第一次调用时为词典构建BK树，之后的查询不需要遍历整个词典；词典改变后
+// 下一次调用时会重新构建。
+func (dict *Dictionary) FuzzyLookup(word string, maxDist int) []FuzzyMatch {
+	if maxDist < 0 {
+		return nil
+	}
+	dict.rebuildIfDirty()
+	root := dict.fuzzyTree()
+	if root == nil {
+		return nil
+	}
+
+	query := []rune(textSliceToString(splitTextToWords([]byte(word))))
+	var matches []FuzzyMatch
+	var indices []int
+	stack := []*bkNode{root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		distance := levenshtein(query, node.runes)
+		if distance <= maxDist {
+			matches = append(matches, FuzzyMatch{Token: dict.token(node.token), Distance: distance})
+			indices = append(indices, node.token)
+		}
+		// 三角不等式：只有到本节点距离在[distance-maxDist, distance+maxDist]内的子树可能有结果
+		for d := distance - maxDist; d <= distance+maxDist; d++ {
+			if child, ok := node.children[d]; ok {
+				stack = append(stack, child)
+			}
+		}
+	}
+
+	sort.Sort(fuzzyMatches{matches, indices})
+	return matches
+}
+
+// 按编辑距离和词典中的顺序排序FuzzyLookup的结果
+type fuzzyMatches struct {
+	matches []FuzzyMatch
+	indices []int
+}
+
+func (fm fuzzyMatches) Len() int { return len(fm.matches) }
+
+func (fm fuzzyMatches) Less(i, j int) bool {
+	if fm.matches[i].Distance != fm.matches[j].Distance {
+		return fm.matches[i].Distance < fm.matches[j].Distance
+	}
+	return fm.indices[i] < fm.indices[j]
+}
+
+func (fm fuzzyMatches) Swap(i, j int) {
+	fm.matches[i], fm.matches[j] = fm.matches[j], fm.matches[i]
+	fm.indices[i], fm.indices[j] = fm.indices[j], fm.indices[i]
+}
+
+// 返回词典的BK树，需要时构建
+func (dict *Dictionary) fuzzyTree() *bkNode {
+	dict.fuzzyLock.Lock()
+	defer dict.fuzzyLock.Unlock()
+	if dict.bkTree == nil && len(dict.tokens) > 0 {
+		dict.bkTree = &bkNode{token: 0, runes: []rune(dict.token(0).Text())}
+		for i := 1; i < len(dict.tokens); i++ {
+			dict.bkTree.insert(i, []rune(dict.token(i).Text()))
+		}
+	}
+	return dict.bkTree
+}
+
+// 向BK树中插入一个分词
+func (node *bkNode) insert(token int, runes []rune) {
+	for {
+		distance := levenshtein(node.runes, runes)
+		child, ok := node.children[distance]
+		if !ok {
+			if node.children == nil {
+				node.children = make(map[int]*bkNode)
+			}
+			node.children[distance] = &bkNode{token: token, runes: runes}
+			return
+		}
+		node = child
+	}
+}
+
+// 计算两个字符串按字符的Levenshtein编辑距离
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(minInt(previous[j]+1, current[j-1]+1), previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
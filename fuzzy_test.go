@@ -0,0 +1,36 @@
+package sego
+
+import (
+	"fmt"
+	"testing"
+)
+
+func fuzzyToString(matches []FuzzyMatch) (output string) {
+	for _, m := range matches {
+		output += fmt.Sprintf("%s/%d ", m.Token.Text(), m.Distance)
+	}
+	return
+}
+
+func TestLevenshtein(t *testing.T) {
+	expect(t, "0", levenshtein([]rune("中国"), []rune("中国")))
+	expect(t, "1", levenshtein([]rune("中国"), []rune("中华")))
+	expect(t, "2", levenshtein([]rune(""), []rune("中国")))
+	expect(t, "3", levenshtein([]rune("kitten"), []rune("sitting")))
+}
+
+func TestFuzzyLookup(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n中华 10 nz\n中国人 10 n\n美国 10 ns\n人口 10 n\nGitHub 10 nz\n")
+	dict := seg.Dictionary()
+
+	expect(t, "中国/0 中华/1 中国人/1 美国/1 ", fuzzyToString(dict.FuzzyLookup("中国", 1)))
+	expect(t, "中国人/1 中国/2 人口/2 ", fuzzyToString(dict.FuzzyLookup("中国人口", 2)))
+	expect(t, "github/1 ", fuzzyToString(dict.FuzzyLookup("GitHab", 1)))
+	expect(t, "", fuzzyToString(dict.FuzzyLookup("日本", 1)))
+	expect(t, "", fuzzyToString(dict.FuzzyLookup("中国", -1)))
+
+	// 词典改变后重建BK树
+	seg.AddToken("英国", 10, "ns")
+	expect(t, "中国/0 中华/1 中国人/1 美国/1 英国/1 ", fuzzyToString(dict.FuzzyLookup("中国", 1)))
+}
@@ -6,4 +6,5 @@ require (
 	github.com/adamzy/cedar-go v0.0.0-20170805034717-80a9c64b256d
 	github.com/adamzy/sego v0.0.0-20151004184924-5eab9a44f8e8
 	github.com/issue9/assert v1.4.1
+	golang.org/x/text v0.3.6
 )
@@ -0,0 +1,78 @@
+package sego
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// HTTP处理器返回的一个分词
+type httpSegment struct {
+	Text         string `json:"text"`
+	Pos          string `json:"pos"`
+	Kind         Kind   `json:"kind"`
+	Start        int    `json:"start"`
+	End          int    `json:"end"`
+	InDictionary bool   `json:"in_dictionary"`
+}
+
+// 请求出错时返回的结果
+type httpError struct {
+	Error string `json:"error"`
+}
+
+type httpHandler struct {
+	segmenter *Segmenter
+}
+
+// 创建使用分词器seg的HTTP处理器
+//
+// 处理器对请求体中的文本分词，返回JSON格式的分词数组，每个分词包含text、pos、
+// kind、start、end（在请求体中的字节位置）和in_dictionary字段。查询参数mode
+// 为search时使用搜索模式，为full或者省略时使用普通模式（见Segment），其它值
+// 返回400和{"error":"..."}。请求体为空时返回空数组[]。
+func NewHTTPHandler(seg *Segmenter) http.Handler {
+	return &httpHandler{segmenter: seg}
+}
+
+func (h *httpHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	var searchMode bool
+	switch mode := req.URL.Query().Get("mode"); mode {
+	case "", "full":
+	case "search":
+		searchMode = true
+	default:
+		writeHTTPError(w, http.StatusBadRequest, "unknown mode: "+mode)
+		return
+	}
+
+	var text []byte
+	if req.Body != nil {
+		var err error
+		if text, err = io.ReadAll(req.Body); err != nil {
+			writeHTTPError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	output := []httpSegment{}
+	for _, segment := range h.segmenter.internalSegment(text, searchMode) {
+		output = append(output, httpSegment{
+			Text:         segment.token.Text(),
+			Pos:          segment.token.Pos(),
+			Kind:         segment.token.Kind(),
+			Start:        segment.start,
+			End:          segment.end,
+			InDictionary: segment.inDictionary,
+		})
+	}
+	json.NewEncoder(w).Encode(output)
+}
+
+// 以JSON格式返回错误
+func writeHTTPError(w http.ResponseWriter, status int, message string) {
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(&httpError{Error: message})
+}
@@ -0,0 +1,40 @@
+package sego
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPHandler(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n中国人 10 n\n")
+	handler := NewHTTPHandler(&seg)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("POST", "/", strings.NewReader("中国人口")))
+	expect(t, "200", recorder.Code)
+	expect(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
+	expect(t, `[{"text":"中国","pos":"ns","kind":"dictionary","start":0,"end":6,"in_dictionary":true},`+
+		`{"text":"人口","pos":"n","kind":"dictionary","start":6,"end":12,"in_dictionary":true}]`+"\n",
+		recorder.Body.String())
+
+	// 搜索模式
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("POST", "/?mode=search", strings.NewReader("中国人")))
+	expect(t, `[{"text":"中国","pos":"ns","kind":"dictionary","start":0,"end":6,"in_dictionary":true},`+
+		`{"text":"人","pos":"x","kind":"pseudo","start":6,"end":9,"in_dictionary":false}]`+"\n",
+		recorder.Body.String())
+
+	// 空请求体返回空数组
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("POST", "/?mode=full", nil))
+	expect(t, "200", recorder.Code)
+	expect(t, "[]\n", recorder.Body.String())
+
+	// 未知的模式
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("POST", "/?mode=fast", strings.NewReader("中国")))
+	expect(t, "400", recorder.Code)
+	expect(t, `{"error":"unknown mode: fast"}`+"\n", recorder.Body.String())
+}
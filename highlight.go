@@ -0,0 +1,52 @@
+package sego
+
+import (
+	"strings"
+)
+
+// 对文本分词，用open和close包围和查询词匹配的分词，返回标记后的文本
+//
+// 分词文本等于某个查询词或者包含某个查询词时匹配，英文不区分大小写，空的查询
+// 词被忽略。连续的匹配分词合在一起包围，比如"<b>中国人口</b>"。文本的其余
+// 部分原样输出，不做HTML转义，需要时由调用者处理。分词器使用了改变文本长度
+// 的规范化选项（比如Unicode规范化）时分词位置可能和原文本不一致，不应使用。
+func Highlight(text []byte, queryTerms []string, seg *Segmenter, open, close string) string {
+	terms := make([]string, 0, len(queryTerms))
+	for _, term := range queryTerms {
+		if term != "" {
+			terms = append(terms, strings.ToLower(term))
+		}
+	}
+
+	var builder strings.Builder
+	position := 0
+	inMatch := false
+	for _, s := range seg.Segment(text) {
+		matched := highlightMatches(s.token.Text(), terms)
+		if matched != inMatch {
+			builder.Write(text[position:s.start])
+			position = s.start
+			if matched {
+				builder.WriteString(open)
+			} else {
+				builder.WriteString(close)
+			}
+			inMatch = matched
+		}
+	}
+	builder.Write(text[position:])
+	if inMatch {
+		builder.WriteString(close)
+	}
+	return builder.String()
+}
+
+// 判断分词文本是否等于或者包含某个查询词
+func highlightMatches(text string, terms []string) bool {
+	for _, term := range terms {
+		if strings.Contains(text, term) {
+			return true
+		}
+	}
+	return false
+}
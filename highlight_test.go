@@ -0,0 +1,22 @@
+package sego
+
+import (
+	"testing"
+)
+
+func TestHighlight(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n中华人民共和国 10 ns\n众多 10 a\n")
+	text := []byte("中华人民共和国人口众多，GitHub上的中国人口")
+
+	expect(t, "中华人民共和国<b>人口</b>众多，GitHub上的中国<b>人口</b>",
+		Highlight(text, []string{"人口"}, &seg, "<b>", "</b>"))
+	// 包含查询词的分词也匹配，连续的匹配合在一起
+	expect(t, "<b>中华人民共和国人口</b>众多，GitHub上的<b>中国人口</b>",
+		Highlight(text, []string{"人口", "人民", "中国"}, &seg, "<b>", "</b>"))
+	expect(t, "中华人民共和国人口众多，[GitHub]上的中国人口",
+		Highlight(text, []string{"GITHUB"}, &seg, "[", "]"))
+	expect(t, "中华人民共和国人口<em>众多</em>",
+		Highlight([]byte("中华人民共和国人口众多"), []string{"众多", ""}, &seg, "<em>", "</em>"))
+	expect(t, "中国", Highlight([]byte("中国"), nil, &seg, "<b>", "</b>"))
+}
@@ -0,0 +1,169 @@
+package sego
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// 会被替换为换行符的块级标签，避免前后两段文字被连在一起
+var htmlBlockTags = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "br": true,
+	"dd": true, "div": true, "dl": true, "dt": true, "footer": true, "form": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"header": true, "hr": true, "li": true, "nav": true, "ol": true, "p": true,
+	"pre": true, "section": true, "table": true, "td": true, "th": true,
+	"title": true, "tr": true, "ul": true,
+}
+
+// 常见的HTML字符实体
+var htmlEntities = map[string]string{
+	"amp": "&", "lt": "<", "gt": ">", "quot": "\"", "apos": "'", "nbsp": " ",
+	"middot": "·", "hellip": "…", "mdash": "—", "ldquo": "“", "rdquo": "”",
+	"lsquo": "‘", "rsquo": "’", "copy": "©", "reg": "®",
+}
+
+// 对HTML分词
+//
+// 先用一个简单的状态机去除HTML标签、注释以及script和style元素的内容，并解码
+// 常见的字符实体，然后对剩下的文本分词。块级标签（比如<p>、<br>）被当作分隔，
+// 两侧的文字不会合成一个分词；行内标签（比如<b>）两侧的文字可以合成一个分词。
+//
+// 去除标签后的文本和Segment一样分词（包括规范化、缓存和分词之后的处理）。
+// 返回的分词位置（包括字符位置）是相对于原HTML的，可以直接用来在原HTML中
+// 高亮关键词。
+func (seg *Segmenter) SegmentHTML(html []byte) []Segment {
+	text, spans := stripHTML(html)
+	// 块级标签产生的分隔符没有对应的HTML文本，被remapSegments去掉
+	return remapSegments(seg.internalSegment(text, false), spans, html)
+}
+
+// 去除HTML标签，返回剩下的文本以及文本中每个字节在原HTML中对应的字节区间
+//
+// 块级标签被替换为一个换行符，其对应区间的start为-1。
+func stripHTML(html []byte) (text []byte, spans []textSpan) {
+	text = make([]byte, 0, len(html))
+	spans = make([]textSpan, 0, len(html))
+	appendText := func(b []byte, start, end int) {
+		for range b {
+			spans = append(spans, textSpan{start: start, end: end})
+		}
+		text = append(text, b...)
+	}
+
+	for current := 0; current < len(html); {
+		switch {
+		case bytes.HasPrefix(html[current:], []byte("<!--")):
+			// 注释
+			end := bytes.Index(html[current+4:], []byte("-->"))
+			if end < 0 {
+				return
+			}
+			current += 4 + end + 3
+
+		case html[current] == '<' && isHTMLTagStart(html[current+1:]):
+			end := htmlTagEnd(html, current)
+			name, closing := htmlTagName(html[current+1 : end])
+			if htmlBlockTags[name] {
+				appendText([]byte{'\n'}, -1, -1)
+			}
+			current = end + 1
+
+			// script和style元素的内容不是文本
+			if !closing && (name == "script" || name == "style") {
+				closeTag := bytes.Index(bytes.ToLower(html[current:]), []byte("</"+name))
+				if closeTag < 0 {
+					return
+				}
+				current += closeTag
+			}
+
+		case html[current] == '&':
+			decoded, length := decodeHTMLEntity(html[current:])
+			if length > 0 {
+				appendText(decoded, current, current+length)
+				current += length
+				break
+			}
+			appendText(html[current:current+1], current, current+1)
+			current++
+
+		default:
+			_, size := utf8.DecodeRune(html[current:])
+			for i := 0; i < size; i++ {
+				spans = append(spans, textSpan{start: current, end: current + size})
+			}
+			text = append(text, html[current:current+size]...)
+			current += size
+		}
+	}
+	return
+}
+
+// 判断'<'之后的文本是否为标签的开始
+func isHTMLTagStart(next []byte) bool {
+	if len(next) == 0 {
+		return false
+	}
+	c := next[0]
+	return c == '/' || c == '!' || c == '?' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// 返回从start开始的标签的结束位置（'>'的位置），引号中的'>'不结束标签
+func htmlTagEnd(html []byte, start int) int {
+	var quote byte
+	for i := start + 1; i < len(html); i++ {
+		switch {
+		case quote != 0:
+			if html[i] == quote {
+				quote = 0
+			}
+		case html[i] == '"' || html[i] == '\'':
+			quote = html[i]
+		case html[i] == '>':
+			return i
+		}
+	}
+	return len(html) - 1
+}
+
+// 返回标签的小写名称以及是否为结束标签，tag为'<'和'>'之间的内容
+func htmlTagName(tag []byte) (name string, closing bool) {
+	if len(tag) > 0 && tag[0] == '/' {
+		closing = true
+		tag = tag[1:]
+	}
+	end := 0
+	for end < len(tag) && tag[end] != ' ' && tag[end] != '/' &&
+		tag[end] != '\t' && tag[end] != '\n' && tag[end] != '\r' && tag[end] != '>' {
+		end++
+	}
+	return strings.ToLower(string(tag[:end])), closing
+}
+
+// 解码text开头的字符实体，返回解码后的文本和实体的长度，不是字符实体时长度为0
+func decodeHTMLEntity(text []byte) ([]byte, int) {
+	end := bytes.IndexByte(text, ';')
+	if end < 2 || end > 10 {
+		return nil, 0
+	}
+	name := string(text[1:end])
+	if name[0] == '#' {
+		var code int64
+		var err error
+		if len(name) > 1 && (name[1] == 'x' || name[1] == 'X') {
+			code, err = strconv.ParseInt(name[2:], 16, 32)
+		} else {
+			code, err = strconv.ParseInt(name[1:], 10, 32)
+		}
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return nil, 0
+		}
+		return []byte(string(rune(code))), end + 1
+	}
+	if decoded, ok := htmlEntities[name]; ok {
+		return []byte(decoded), end + 1
+	}
+	return nil, 0
+}
@@ -0,0 +1,51 @@
+package sego
+
+import (
+	"testing"
+)
+
+func htmlSegmentsToString(html []byte, segs []Segment) (output string) {
+	for _, s := range segs {
+		output += string(html[s.start:s.end]) + "/" + s.token.Text() + " "
+	}
+	return
+}
+
+func TestSegmentHTML(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n众多 10 a\n")
+
+	html := []byte(`<p class="a>b">中<b>国</b>人口</p><p>众多&amp;ok&#x4E2D;国</p>`)
+	segments := seg.SegmentHTML(html)
+	expect(t, "中<b>国/中国 人口/人口 众多/众多 &amp;/& ok/ok &#x4E2D;国/中国 ",
+		htmlSegmentsToString(html, segments))
+	expect(t, "15", segments[0].start)
+	expect(t, "24", segments[0].end)
+	expect(t, "15", segments[0].runeStart)
+	expect(t, "20", segments[0].runeEnd)
+
+	// 注释、script和style的内容被去除
+	html = []byte("<!-- 中国 --><script>var a = '<b>';</script><style>p{}</style>人口<br/>众多")
+	segments = seg.SegmentHTML(html)
+	expect(t, "人口/人口 众多/众多 ", htmlSegmentsToString(html, segments))
+
+	// 不是标签的'<'和不认识的实体原样保留
+	html = []byte("a < b &foo; c")
+	expect(t, "a/a  /  </<  /  b/b  /  &/& foo/foo ;/;  /  c/c ",
+		htmlSegmentsToString(html, seg.SegmentHTML(html)))
+
+	expect(t, "0", len(seg.SegmentHTML([]byte("<p></p>"))))
+
+	// 和Segment一样规范化文本并做分词之后的处理，位置仍然相对于原HTML
+	processed := NewSegmenter(WithFullWidthNormalization(), WithCollapseRepeats(true))
+	processed.LoadDictionary("iphone 10 nz\n手机 10 n\n")
+	processed.EnableSentenceCache(1<<20, 0)
+	html = []byte("<p>ＩＰｈｏｎｅ</p>手机！！！")
+	for i := 0; i < 2; i++ {
+		// 第二次命中句子缓存
+		segments = processed.SegmentHTML(html)
+		expect(t, "ＩＰｈｏｎｅ/iphone 手机/手机 ！！！/！！！ ", htmlSegmentsToString(html, segments))
+		expect(t, "3", segments[0].runeStart)
+		expect(t, "9", segments[0].runeEnd)
+	}
+}
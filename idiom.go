@@ -0,0 +1,60 @@
+package sego
+
+import (
+	"bufio"
+	_ "embed"
+	"io"
+	"os"
+	"strings"
+)
+
+// 内置的成语表，见LoadBuiltinIdioms
+//
+//go:embed data/idioms.txt
+var builtinIdioms string
+
+// 从文件中载入成语词典
+//
+// 文件每行一个成语，只取每行的第一个字段，因此也可以直接使用普通词典格式的
+// 文件；空行和#开头的注释行被忽略。每个成语作为词性为"i"的强制分词加入词典
+// （见ForceWord）：文本中出现的成语总是保持完整，并且标注为"i"。
+//
+// 载入词典会替换掉之前加入的成语，因此该函数需要在LoadDictionary之后调用，
+// 且不能和分词并发调用。
+func (seg *Segmenter) LoadIdiomDictionary(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return seg.loadIdioms(file)
+}
+
+// 载入内置的成语表
+//
+// 表中的539个成语是从dictionary.txt中词性为i、词频最高的1000个四字词中整理
+// 出来的，去掉了繁体字重复项以及专名、术语等不是成语的词。这个表只覆盖较常见
+// 的成语，需要完整的成语词典时使用LoadIdiomDictionary。
+//
+// 调用时机和效果同LoadIdiomDictionary，载入出错时返回错误。
+func (seg *Segmenter) LoadBuiltinIdioms() error {
+	return seg.loadIdioms(strings.NewReader(builtinIdioms))
+}
+
+func (seg *Segmenter) loadIdioms(reader io.Reader) error {
+	numIdioms := 0
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		seg.ForceWord(fields[0], "i")
+		numIdioms++
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	seg.logInfo("sego成语词典载入完毕", "idioms_loaded", numIdioms)
+	return nil
+}
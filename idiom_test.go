@@ -0,0 +1,39 @@
+package sego
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestLoadIdiomDictionary(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("一心 10 n\n一意 10 n\n心花 20 n\n怒放 20 v\n他 10 r\n")
+	text := []byte("他一心一意，心花怒放")
+	expect(t, "他/r 一心/n 一意/n ，/w 心花/n 怒放/v ", SegmentsToString(seg.Segment(text), false))
+
+	expect(t, "<nil>", seg.LoadIdiomDictionary("testdata/test_idioms.txt"))
+	expect(t, "他/r 一心一意/i ，/w 心花怒放/i ", SegmentsToString(seg.Segment(text), false))
+
+	expect(t, "true", seg.LoadIdiomDictionary("testdata/not_exist.txt") != nil)
+}
+
+func TestLoadBuiltinIdioms(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("莫名 10 n\n其妙 10 n\n大吃 10 v\n一惊 10 n\n")
+	expect(t, "<nil>", seg.LoadBuiltinIdioms())
+	expect(t, "543", seg.Dictionary().NumTokens())
+	expect(t, "莫名其妙/i 大吃一惊/i ", SegmentsToString(seg.Segment([]byte("莫名其妙大吃一惊")), false))
+
+	// 表中没有重复项，每个成语都是四个字
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(builtinIdioms, "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		expect(t, "false", seen[line])
+		expect(t, "4", utf8.RuneCountInString(line))
+		seen[line] = true
+	}
+	expect(t, "539", len(seen))
+}
@@ -0,0 +1,124 @@
+package sego
+
+import (
+	"sort"
+	"sync"
+)
+
+// 倒排索引，记录每个词出现在哪些文档中，用于中文全文搜索
+//
+// 可以并发地建索引和查询。
+type InvertedIndex struct {
+	seg      *Segmenter
+	lock     sync.RWMutex
+	postings map[string][]int // 每个词所在的文档编号，从小到大排列且不重复
+}
+
+// 创建一个用seg分词的倒排索引
+func NewInvertedIndex(seg *Segmenter) *InvertedIndex {
+	return &InvertedIndex{seg: seg, postings: make(map[string][]int)}
+}
+
+// 将文档加入索引
+//
+// 文档用搜索模式分词（见SegmentsToSlice），因此"中华人民共和国"也可以用
+// "人民"或"共和国"查到。空白和标点不建索引。同一文档编号可以多次加入，
+// 各次的词合并在一起。
+func (index *InvertedIndex) Index(docID int, text []byte) {
+	words := SegmentsToSlice(index.seg.Segment(text), true)
+
+	index.lock.Lock()
+	defer index.lock.Unlock()
+	for _, word := range words {
+		if !isCollocationWord(word) {
+			continue
+		}
+		docs := index.postings[word]
+		i := sort.SearchInts(docs, docID)
+		if i < len(docs) && docs[i] == docID {
+			continue
+		}
+		docs = append(docs, 0)
+		copy(docs[i+1:], docs[i:])
+		docs[i] = docID
+		index.postings[word] = docs
+	}
+}
+
+// 返回包含所有words的文档编号，从小到大排列
+//
+// 多个词之间是"与"的关系。查询词和文档一样经过规范化，英文不区分大小写。
+// 没有给出词时返回空。
+func (index *InvertedIndex) Lookup(words ...string) []int {
+	index.lock.RLock()
+	defer index.lock.RUnlock()
+
+	output := []int{}
+	for i, word := range words {
+		key := textSliceToString(index.seg.splitText(index.seg.normalizeText([]byte(word))))
+		docs := index.postings[key]
+		if i == 0 {
+			output = append(output, docs...)
+		} else {
+			output = intersectSorted(output, docs)
+		}
+		if len(output) == 0 {
+			break
+		}
+	}
+	return output
+}
+
+// 返回词的数目
+func (index *InvertedIndex) NumWords() int {
+	index.lock.RLock()
+	defer index.lock.RUnlock()
+	return len(index.postings)
+}
+
+// 对文本分词，返回每个不同的分词文本在文本中出现的所有字节区间[start, end)
+//
+// 每个分词文本的区间按在文本中出现的先后排列，和map的遍历顺序无关，可以直接
+// 用来高亮查询词的所有出现位置。分词文本是规范化后的文本（英文为小写），
+// 区间对应的原文本见Segment.Bytes。
+func (seg *Segmenter) SegmentIndex(bytes []byte) map[string][][2]int {
+	return segmentIndex(seg.Segment(bytes), false)
+}
+
+// 和SegmentIndex相同，但只包括词典中的分词（见Segment.InDictionary）
+//
+// 词典中没有的分词都不包括，除了伪分词（单字、空白和标点）以外还有数字、网址、
+// 电子邮件地址、保护模式匹配的文本和非法的UTF8字节。
+func (seg *Segmenter) SegmentIndexWithoutPseudo(bytes []byte) map[string][][2]int {
+	return segmentIndex(seg.Segment(bytes), true)
+}
+
+func segmentIndex(segs []Segment, dictionaryOnly bool) map[string][][2]int {
+	output := make(map[string][][2]int)
+	for i := range segs {
+		if dictionaryOnly && !segs[i].InDictionary() {
+			continue
+		}
+		text := segs[i].token.Text()
+		output[text] = append(output[text], [2]int{segs[i].start, segs[i].end})
+	}
+	return output
+}
+
+// 求两个从小到大排列的列表的交集，结果写回a
+func intersectSorted(a, b []int) []int {
+	output := a[:0]
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			output = append(output, a[i])
+			i++
+			j++
+		}
+	}
+	return output
+}
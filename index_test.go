@@ -0,0 +1,68 @@
+package sego
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestInvertedIndex(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中华 10 nz\n人民 10 n\n共和国 10 ns\n中华人民共和国 10 ns\n万岁 10 i\n")
+	index := NewInvertedIndex(&seg)
+	index.Index(3, []byte("中华人民共和国万岁！"))
+	index.Index(1, []byte("人民万岁"))
+	index.Index(2, []byte("GitHub，人民"))
+	index.Index(1, []byte("人民"))
+
+	expect(t, "[1 2 3]", fmt.Sprint(index.Lookup("人民")))
+	expect(t, "[3]", fmt.Sprint(index.Lookup("共和国")))
+	expect(t, "[3]", fmt.Sprint(index.Lookup("中华人民共和国")))
+	expect(t, "[1 3]", fmt.Sprint(index.Lookup("人民", "万岁")))
+	expect(t, "[2]", fmt.Sprint(index.Lookup("GITHUB", "人民")))
+	expect(t, "[]", fmt.Sprint(index.Lookup("人民", "不存在")))
+	expect(t, "[]", fmt.Sprint(index.Lookup("，")))
+	expect(t, "[]", fmt.Sprint(index.Lookup()))
+	expect(t, "6", index.NumWords())
+}
+
+func TestInvertedIndexConcurrent(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n")
+	index := NewInvertedIndex(&seg)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			index.Index(i, []byte("中国人口"))
+			index.Lookup("中国")
+		}(i)
+	}
+	wg.Wait()
+	expect(t, "20", len(index.Lookup("中国", "人口")))
+}
+
+func TestSegmentIndex(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n")
+	text := []byte("中国人口，GitHub中国13亿人口中国")
+
+	index := seg.SegmentIndex(text)
+	expect(t, "[[0 6] [21 27] [38 44]]", fmt.Sprint(index["中国"]))
+	expect(t, "[[6 12] [32 38]]", fmt.Sprint(index["人口"]))
+	expect(t, "[[15 21]]", fmt.Sprint(index["github"]))
+	expect(t, "GitHub", string(text[index["github"][0][0]:index["github"][0][1]]))
+	expect(t, "[[12 15]]", fmt.Sprint(index["，"]))
+	expect(t, "6", len(index))
+
+	// 只包括词典中的分词，数字和英文也不包括
+	index = seg.SegmentIndexWithoutPseudo(text)
+	expect(t, "map[中国:[[0 6] [21 27] [38 44]] 人口:[[6 12] [32 38]]]", fmt.Sprint(index))
+
+	seg.EnableURLProtection()
+	index = seg.SegmentIndexWithoutPseudo([]byte("中国 https://example.com 人口"))
+	expect(t, "map[中国:[[0 6]] 人口:[[27 33]]]", fmt.Sprint(index))
+
+	expect(t, "map[]", fmt.Sprint(seg.SegmentIndex(nil)))
+}
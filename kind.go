@@ -0,0 +1,58 @@
+package sego
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 分词的来源类别，用来区分词典中的分词和分词器临时生成的分词
+type Kind int
+
+const (
+	KindDictionary Kind = iota // 词典中的分词（包括AddToken和ForceWord加入的分词）
+	KindPseudo                 // 词典中没有的单个字元，词性按字符类别为"w"、"s"或"x"
+	KindNumber                 // 词典中没有的数字，词性为"m"
+	KindURL                    // 网址，见EnableURLProtection
+	KindEmail                  // 电子邮件地址，见EnableEmailProtection
+	KindProtected              // 匹配AddProtectPattern加入的保护模式的文本
+	KindInvalid                // 非法的UTF8字节，词性为"err"
+)
+
+var kindNames = [...]string{
+	KindDictionary: "dictionary",
+	KindPseudo:     "pseudo",
+	KindNumber:     "number",
+	KindURL:        "url",
+	KindEmail:      "email",
+	KindProtected:  "protected",
+	KindInvalid:    "invalid",
+}
+
+// 返回类别的名称，比如"dictionary"
+func (kind Kind) String() string {
+	if kind >= 0 && int(kind) < len(kindNames) {
+		return kindNames[kind]
+	}
+	return fmt.Sprintf("Kind(%d)", int(kind))
+}
+
+// 实现encoding.TextMarshaler，JSON中输出类别的名称
+func (kind Kind) MarshalText() ([]byte, error) {
+	return []byte(kind.String()), nil
+}
+
+// 返回词典中没有的字元对应的伪分词的类别
+func pseudoKind(word Text) Kind {
+	if isInvalidUTF8Word(word) {
+		return KindInvalid
+	}
+	for current := 0; current < len(word); {
+		r, size := utf8.DecodeRune(word[current:])
+		if !unicode.IsDigit(r) {
+			return KindPseudo
+		}
+		current += size
+	}
+	return KindNumber
+}
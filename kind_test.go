@@ -0,0 +1,31 @@
+package sego
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func kindsToString(segs []Segment) (output string) {
+	for _, s := range segs {
+		output += s.token.Text() + "/" + s.token.Kind().String() + " "
+	}
+	return
+}
+
+func TestKind(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n")
+	seg.EnableURLProtection()
+	seg.EnableEmailProtection()
+
+	expect(t, "中国/dictionary 有/pseudo 13/number 亿/pseudo 人口/dictionary \xff/invalid ",
+		kindsToString(seg.Segment([]byte("中国有13亿人口\xff"))))
+	expect(t, "见/pseudo http://www.example.com/url 或/pseudo a@example.com/email ",
+		kindsToString(seg.Segment([]byte("见http://www.example.com或a@example.com"))))
+
+	expect(t, "dictionary", KindDictionary)
+	expect(t, "Kind(100)", Kind(100))
+	bytes, err := json.Marshal(map[string]Kind{"kind": KindNumber})
+	expect(t, "<nil>", err)
+	expect(t, "{\"kind\":\"number\"}", string(bytes))
+}
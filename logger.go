@@ -0,0 +1,124 @@
+package sego
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+)
+
+// sego输出日志使用的logger
+var logger = log.Default()
+
+// 设置sego输出日志使用的logger，默认为标准库的log.Default()
+//
+// l为nil时不输出任何日志。该函数不是并发安全的，应在使用分词器之前调用。
+func SetLogger(l *log.Logger) {
+	if l == nil {
+		l = log.New(io.Discard, "", 0)
+	}
+	logger = l
+}
+
+// 结构化的日志输出，SlogLogger实现了该接口
+//
+// args为交替出现的键和值，比如"tokens_loaded", 100。实现了该接口的Logger直接
+// 收到键值对，其他Logger收到由formatLogMessage拼接的一行文本。
+type structuredLogger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// 日志接口，用来把分词器的日志接入zap、zerolog、logrus等日志库
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// 不输出任何日志的Logger
+type NoopLogger struct{}
+
+func (NoopLogger) Debugf(format string, args ...interface{}) {}
+func (NoopLogger) Infof(format string, args ...interface{})  {}
+func (NoopLogger) Errorf(format string, args ...interface{}) {}
+
+// 输出到标准库log.Logger的Logger，每条日志前加上级别，比如"[INFO] "
+type StdLogger struct {
+	logger *log.Logger
+}
+
+// 创建一个输出到l的StdLogger，l为nil时使用log.Default()
+func NewStdLogger(l *log.Logger) *StdLogger {
+	if l == nil {
+		l = log.Default()
+	}
+	return &StdLogger{logger: l}
+}
+
+func (l *StdLogger) Debugf(format string, args ...interface{}) {
+	l.logger.Printf("[DEBUG] "+format, args...)
+}
+
+func (l *StdLogger) Infof(format string, args ...interface{}) {
+	l.logger.Printf("[INFO] "+format, args...)
+}
+
+func (l *StdLogger) Errorf(format string, args ...interface{}) {
+	l.logger.Printf("[ERROR] "+format, args...)
+}
+
+// 设置分词器输出日志使用的Logger
+//
+// 设置后分词器的日志输出到l而不是包的logger（见SetLogger），键值对以" 键=值"
+// 的形式附加在消息之后；l同时实现了Debug、Info和Error（比如SlogLogger）时直接
+// 收到键值对。l为nil时恢复使用包的logger。该函数不能和分词并发调用。
+func (seg *Segmenter) SetLogger(l Logger) {
+	seg.customLogger = l
+}
+
+// 将msg和键值对格式化为一行文本，用于不支持结构化日志的Logger
+func formatLogMessage(msg string, args []interface{}) string {
+	var builder strings.Builder
+	builder.WriteString(msg)
+	for i := 0; i+1 < len(args); i += 2 {
+		fmt.Fprintf(&builder, " %v=%v", args[i], args[i+1])
+	}
+	return builder.String()
+}
+
+// 输出调试日志，没有设置Logger时不输出
+func (seg *Segmenter) logDebug(msg string, args ...interface{}) {
+	switch l := seg.customLogger.(type) {
+	case nil:
+	case structuredLogger:
+		l.Debug(msg, args...)
+	default:
+		l.Debugf("%s", formatLogMessage(msg, args))
+	}
+}
+
+// 输出普通日志，没有设置Logger时只将msg输出到包的logger
+func (seg *Segmenter) logInfo(msg string, args ...interface{}) {
+	switch l := seg.customLogger.(type) {
+	case nil:
+		logger.Println(msg)
+	case structuredLogger:
+		l.Info(msg, args...)
+	default:
+		l.Infof("%s", formatLogMessage(msg, args))
+	}
+}
+
+// 输出错误日志，没有设置Logger时将msg输出到包的logger
+func (seg *Segmenter) logError(msg string, args ...interface{}) {
+	switch l := seg.customLogger.(type) {
+	case nil:
+		logger.Println(msg)
+	case structuredLogger:
+		l.Error(msg, args...)
+	default:
+		l.Errorf("%s", formatLogMessage(msg, args))
+	}
+}
@@ -0,0 +1,46 @@
+package sego
+
+import (
+	"bytes"
+	"log"
+	"testing"
+)
+
+func TestSetLogger(t *testing.T) {
+	defer SetLogger(log.Default())
+
+	var buf bytes.Buffer
+	SetLogger(log.New(&buf, "[sego] ", 0))
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n")
+	expect(t, "[sego] sego词典字符串载入完毕\n", buf.String())
+
+	buf.Reset()
+	SetLogger(nil)
+	seg.LoadDictionary("中国 10 ns\n")
+	expect(t, "", buf.String())
+}
+
+func TestSegmenterSetLogger(t *testing.T) {
+	defer SetLogger(log.Default())
+	var global bytes.Buffer
+	SetLogger(log.New(&global, "", 0))
+
+	var buf bytes.Buffer
+	var seg Segmenter
+	seg.SetLogger(NewStdLogger(log.New(&buf, "", 0)))
+	seg.LoadDictionary("中国 10 ns\n人口\n")
+	expect(t, "[DEBUG] sego词典跳过一行 line=2 text=人口\n"+
+		"[INFO] sego词典字符串载入完毕 tokens_loaded=1 lines_skipped=1\n", buf.String())
+	expect(t, "", global.String())
+
+	buf.Reset()
+	seg.SetLogger(NoopLogger{})
+	seg.LoadDictionary("中国 10 ns\n")
+	expect(t, "", buf.String())
+	expect(t, "", global.String())
+
+	seg.SetLogger(nil)
+	seg.LoadDictionary("中国 10 ns\n")
+	expect(t, "sego词典字符串载入完毕\n", global.String())
+}
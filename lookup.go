@@ -0,0 +1,170 @@
+package sego
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// 返回词典中所有以prefix开头的分词，按频率从高到低排序，频率相同时按词典中的顺序
+//
+// 英文不区分大小写。查找从前缀树中prefix对应的节点开始向下遍历，不需要遍历
+// 整个词典，可以用于输入时的自动补全。
+func (dict *Dictionary) LookupPrefix(prefix string) []*Token {
+	key := textSliceToBytes(splitTextToWords([]byte(prefix)))
+
+	// 载入异体字映射表后同一个分词可能有多个键
+	seen := make(map[int]bool)
+	var indices []int
+	for _, id := range dict.trie.PrefixPredict(key, 0) {
+		value, err := dict.trie.Value(id)
+		if err != nil || seen[value] {
+			continue
+		}
+		seen[value] = true
+		indices = append(indices, value)
+	}
+
+	sort.Slice(indices, func(i, j int) bool {
+		fi, fj := dict.token(indices[i]).frequency, dict.token(indices[j]).frequency
+		if fi != fj {
+			return fi > fj
+		}
+		return indices[i] < indices[j]
+	})
+	tokens := make([]*Token, len(indices))
+	for i, value := range indices {
+		tokens[i] = dict.token(value)
+	}
+	return tokens
+}
+
+// 返回词典中以prefix开头的分词文本，最多limit个，按频率从高到低排序
+//
+// limit小于等于零时返回所有的分词。见LookupPrefix。
+func (dict *Dictionary) WordsWithPrefix(prefix string, limit int) []string {
+	tokens := dict.LookupPrefix(prefix)
+	if limit > 0 && limit < len(tokens) {
+		tokens = tokens[:limit]
+	}
+	words := make([]string, len(tokens))
+	for i, token := range tokens {
+		words[i] = token.Text()
+	}
+	return words
+}
+
+// 返回词典中所有匹配通配模式pattern的分词，按词典中的顺序排列
+//
+// 模式中"*"匹配任意个（包括零个）字符，"?"匹配恰好一个字符，比如"中?"匹配
+// "中国"但不匹配"中华人民"，"*国"匹配所有以"国"结尾的分词。英文不区分大小写。
+// 第一个通配符之前的部分用前缀树查找，之后的部分用回溯法匹配，因此模式以
+// 通配符开头时需要遍历整个词典。
+func (dict *Dictionary) LookupPattern(pattern string) []*Token {
+	pattern = textSliceToString(splitTextToWords([]byte(pattern)))
+	prefix := pattern
+	if i := strings.IndexAny(pattern, "*?"); i >= 0 {
+		prefix = pattern[:i]
+	}
+	patternRunes := []rune(pattern)
+
+	// 载入异体字映射表后同一个分词可能有多个键
+	seen := make(map[int]bool)
+	var indices []int
+	for _, id := range dict.trie.PrefixPredict([]byte(prefix), 0) {
+		value, err := dict.trie.Value(id)
+		if err != nil || seen[value] {
+			continue
+		}
+		seen[value] = true
+		if matchPattern(patternRunes, []rune(dict.token(value).Text())) {
+			indices = append(indices, value)
+		}
+	}
+
+	sort.Ints(indices)
+	tokens := make([]*Token, len(indices))
+	for i, value := range indices {
+		tokens[i] = dict.token(value)
+	}
+	return tokens
+}
+
+// 判断text是否匹配通配模式pattern
+//
+// 遇到"*"时记下位置，之后不匹配时回溯到最近的"*"让它多匹配一个字符。
+func matchPattern(pattern, text []rune) bool {
+	p, t := 0, 0
+	star, starText := -1, 0
+	for t < len(text) {
+		switch {
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == text[t]):
+			p++
+			t++
+		case p < len(pattern) && pattern[p] == '*':
+			star, starText = p, t
+			p++
+		case star >= 0:
+			starText++
+			p, t = star+1, starText
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
+
+// 返回text中从字节位置offset开始的最长的词典分词，不运行最短路径算法
+//
+// 从offset开始的文本按分词器的设置划分成字元后在前缀树中查找，只返回包含至少
+// 两个字元的分词，没有这样的分词时返回false。返回的分词位置是在text中的绝对
+// 位置。offset应当在字元的边界上；文本不做Unicode规范化等转换。可以用于流式
+// 的增量匹配和高亮显示。每次调用只划分offset之后够用的一段文本，在长文本中
+// 逐个位置调用时不会重复划分整个文本（返回的字符位置仍需要从text开头计数）。
+func (seg *Segmenter) FindLongestMatch(text []byte, offset int) (Segment, bool) {
+	if seg.dict == nil || offset < 0 || offset >= len(text) {
+		return Segment{}, false
+	}
+	seg.dict.rebuildIfDirty()
+	maxTokenLength := seg.dict.maxTokenLength
+	if maxTokenLength < 2 {
+		return Segment{}, false
+	}
+
+	// 只划分offset之后足够长的一段文本，使每次调用的代价和文本长度无关。窗口在
+	// 字符边界处截断，只有最后一个字元可能不完整，因此字元数多于maxTokenLength
+	// 时前maxTokenLength个字元和划分整个文本的结果相同
+	var words []Text
+	for limit := maxTokenLength * utf8.UTFMax; ; limit *= 2 {
+		end := offset + limit
+		if end >= len(text) {
+			words = seg.splitText(text[offset:])
+			break
+		}
+		for end > offset && !utf8.RuneStart(text[end]) {
+			end--
+		}
+		words = seg.splitText(text[offset:end])
+		if len(words) > maxTokenLength {
+			break
+		}
+	}
+	tokens := make([]*Token, maxTokenLength)
+	numTokens := seg.dict.lookupTokens(words[:minInt(maxTokenLength, len(words))], tokens)
+	if numTokens == 0 || len(tokens[numTokens-1].text) < 2 {
+		return Segment{}, false
+	}
+
+	token := tokens[numTokens-1]
+	runeStart := utf8.RuneCount(text[:offset])
+	return Segment{
+		start:     offset,
+		end:       offset + textSliceByteLength(token.text),
+		runeStart: runeStart,
+		runeEnd:   runeStart + textSliceRuneCount(token.text),
+		token:     token,
+	}, true
+}
@@ -0,0 +1,117 @@
+package sego
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func tokensToString(tokens []*Token) (output string) {
+	for _, token := range tokens {
+		output += token.Text() + "/" + token.Pos() + " "
+	}
+	return
+}
+
+func TestLookupPrefix(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n中 5 f\n中国人 20 n\n中华 10 nz\n人口 10 n\nGitHub 10 nz\nGit 30 nz\n國家 5 n\n")
+	dict := seg.Dictionary()
+
+	expect(t, "中国人/n 中国/ns 中华/nz 中/f ", tokensToString(dict.LookupPrefix("中")))
+	expect(t, "中国人/n 中国/ns ", tokensToString(dict.LookupPrefix("中国")))
+	expect(t, "git/nz github/nz ", tokensToString(dict.LookupPrefix("GIT")))
+	expect(t, "", tokensToString(dict.LookupPrefix("日本")))
+	expect(t, "8", len(dict.LookupPrefix("")))
+
+	// 同一个分词的多个键只返回一次
+	expect(t, "<nil>", dict.LoadVariantMap("testdata/test_variants.txt"))
+	expect(t, "國家/n ", tokensToString(dict.LookupPrefix("国")))
+	expect(t, "8", len(dict.LookupPrefix("")))
+}
+
+func TestWordsWithPrefix(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n中 5 f\n中国人 20 n\n中华 10 nz\n人口 10 n\n")
+	dict := seg.Dictionary()
+
+	expect(t, "[中国人 中国 中华 中]", dict.WordsWithPrefix("中", 0))
+	expect(t, "[中国人 中国]", dict.WordsWithPrefix("中", 2))
+	expect(t, "[中国人 中国]", dict.WordsWithPrefix("中国", 10))
+	expect(t, "[]", dict.WordsWithPrefix("日", 10))
+}
+
+func TestMatchPattern(t *testing.T) {
+	for _, c := range []struct {
+		pattern, text, match string
+	}{
+		{"中国", "中国", "true"},
+		{"中?", "中国", "true"},
+		{"中?", "中国人", "false"},
+		{"*国", "中华民国", "true"},
+		{"*国*", "中国人", "true"},
+		{"中*人", "中国人", "true"},
+		{"中*人", "中国人口", "false"},
+		{"*", "", "true"},
+		{"?", "", "false"},
+		{"a*b*c", "aXbYbZc", "true"},
+		{"a*b?c", "abbc", "true"},
+	} {
+		expect(t, c.match, matchPattern([]rune(c.pattern), []rune(c.text)))
+	}
+}
+
+func TestLookupPattern(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n中 5 f\n中国人 20 n\n中华 10 nz\n美国 10 ns\n人口 10 n\nGitHub 10 nz\nGit 30 nz\n")
+	dict := seg.Dictionary()
+
+	expect(t, "中国/ns 中华/nz ", tokensToString(dict.LookupPattern("中?")))
+	expect(t, "中国/ns 中国人/n ", tokensToString(dict.LookupPattern("中国*")))
+	expect(t, "中国/ns 美国/ns ", tokensToString(dict.LookupPattern("*国")))
+	expect(t, "中国人/n 人口/n ", tokensToString(dict.LookupPattern("*人*")))
+	expect(t, "github/nz ", tokensToString(dict.LookupPattern("GIT?U*")))
+	expect(t, "中/f ", tokensToString(dict.LookupPattern("中")))
+	expect(t, "", tokensToString(dict.LookupPattern("日*")))
+	expect(t, "8", len(dict.LookupPattern("*")))
+}
+
+func TestFindLongestMatch(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n中国人 10 n\n人口 10 n\n有 10 v\nGitHub 10 nz\n")
+	text := []byte("中国人口，有GitHub")
+
+	match, ok := seg.FindLongestMatch(text, 0)
+	expect(t, "true", ok)
+	expect(t, "中国人/n ", SegmentsToString([]Segment{match}, false))
+	expect(t, "0 9 0 3", fmt.Sprint(match.start, match.end, match.runeStart, match.runeEnd))
+
+	match, ok = seg.FindLongestMatch(text, 6)
+	expect(t, "true", ok)
+	expect(t, "人口/n ", SegmentsToString([]Segment{match}, false))
+	expect(t, "6 12 2 4", fmt.Sprint(match.start, match.end, match.runeStart, match.runeEnd))
+
+	// 单字元的分词不算匹配
+	_, ok = seg.FindLongestMatch(text, 15)
+	expect(t, "false", ok)
+	_, ok = seg.FindLongestMatch(text, 12)
+	expect(t, "false", ok)
+	_, ok = seg.FindLongestMatch(text, 100)
+	expect(t, "false", ok)
+
+	var empty Segmenter
+	_, ok = empty.FindLongestMatch(text, 0)
+	expect(t, "false", ok)
+
+	// 字元比划分的窗口更长时扩大窗口
+	var long Segmenter
+	long.LoadDictionary("abcdefghijklmnopqrstuvwxyz中 10 nz\n国人 10 n\n")
+	text = []byte(strings.Repeat("国人", 10) + "ABCDEFGHIJKLMNOPQRSTUVWXYZ中国" + strings.Repeat("人口", 100))
+	match, ok = long.FindLongestMatch(text, 60)
+	expect(t, "true", ok)
+	expect(t, "abcdefghijklmnopqrstuvwxyz中/nz ", SegmentsToString([]Segment{match}, false))
+	expect(t, "60 89 20 47", fmt.Sprint(match.start, match.end, match.runeStart, match.runeEnd))
+	match, ok = long.FindLongestMatch(text, 6)
+	expect(t, "true", ok)
+	expect(t, "6 12", fmt.Sprint(match.start, match.end))
+}
@@ -0,0 +1,101 @@
+package sego
+
+import (
+	"strings"
+	"unicode"
+)
+
+// 中文数词中使用的字
+const chineseNumerals = "零〇一二两三四五六七八九十百千万亿壹贰叁肆伍陆柒捌玖拾佰仟萬億几半"
+
+// 常用量词，词性为"q"的分词也被认为是量词
+var measureWords = map[string]bool{}
+
+func init() {
+	for _, word := range strings.Fields(`
+		个 件 张 只 条 本 位 名 次 回 遍 年 月 日 天 号 岁 元 块 角 毛 斤 公斤 克 吨 米
+		公里 里 尺 寸 头 匹 辆 台 架 艘 座 间 家 支 枝 双 对 副 种 类 套 份 把 杯 瓶
+		碗 盘 片 层 项 篇 首 部 封 根 颗 粒 枚 点 分 秒 分钟 小时 周 倍 群 批 场 所
+		棵 株 朵 道 段 句 页 户 口 幅 顿 趟 声 下 届 期 章 节 串 堆 排 行 笔`) {
+		measureWords[word] = true
+	}
+}
+
+// 将数词和紧随其后的量词合并为一个词性为"m"的分词，比如"三/m 个/q"合并为
+// "三个/m"
+//
+// 数词是全部由中文数字（包括大写数字和"两"、"几"、"半"）或阿拉伯数字组成的
+// 分词，量词是常用量词表中的分词或者词性为"q"的分词。合并后的分词位置覆盖
+// 两个分词，词频和路径长度取自数词。返回新的切片，不改变segs。
+func MergeNumMeasure(segs []Segment) []Segment {
+	return mergeNumMeasure(segs, isNumeral, isMeasureWord)
+}
+
+// 对文本分词，并将数词和紧随其后的量词合并为一个词性为"m"的分词，比如
+// "三百块"分为"三百块/m"
+//
+// 数词是词性为"m"的分词或者全部由数字组成的分词，量词是词性为"q"的分词或者
+// WithMeasureWords设置的量词（没有设置时使用MergeNumMeasure的常用量词表）。
+// 合并后的分词位置覆盖两个分词，原分词可以用Segment.Components取得。
+func (seg *Segmenter) SegmentQuantities(bytes []byte) []Segment {
+	return mergeNumMeasure(seg.Segment(bytes), isQuantityNumber, seg.isQuantityMeasure)
+}
+
+// 设置SegmentQuantities中和数词合并的量词（词性为"q"的分词总是被合并），
+// 不设置时使用常用量词表
+func WithMeasureWords(words ...string) Option {
+	return func(seg *Segmenter) {
+		seg.measureWords = make(map[string]bool, len(words))
+		for _, word := range words {
+			seg.measureWords[strings.ToLower(word)] = true
+		}
+	}
+}
+
+// 将满足isNumber的分词和紧随其后的满足isMeasure的分词合并，返回新的切片
+func mergeNumMeasure(segs []Segment, isNumber, isMeasure func(*Token) bool) []Segment {
+	output := make([]Segment, 0, len(segs))
+	for i := 0; i < len(segs); i++ {
+		if i+1 < len(segs) && isNumber(segs[i].token) && isMeasure(segs[i+1].token) {
+			number := segs[i].token
+			base := &Token{frequency: number.frequency, distance: number.distance, pos: "m", kind: number.kind}
+			output = append(output, mergeSegments(segs[i:i+2], base))
+			i++
+			continue
+		}
+		output = append(output, segs[i])
+	}
+	return output
+}
+
+// 判断分词是否为数词
+func isNumeral(token *Token) bool {
+	text := token.Text()
+	if text == "" {
+		return false
+	}
+	for _, r := range text {
+		if !unicode.IsDigit(r) && !strings.ContainsRune(chineseNumerals, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// 判断分词是否为量词
+func isMeasureWord(token *Token) bool {
+	return token.pos == "q" || measureWords[token.Text()]
+}
+
+// 判断分词是否为SegmentQuantities中的数词
+func isQuantityNumber(token *Token) bool {
+	return token.pos == "m" || isNumeral(token)
+}
+
+// 判断分词是否为SegmentQuantities中的量词
+func (seg *Segmenter) isQuantityMeasure(token *Token) bool {
+	if seg.measureWords == nil {
+		return isMeasureWord(token)
+	}
+	return token.pos == "q" || seg.measureWords[token.Text()]
+}
@@ -0,0 +1,48 @@
+package sego
+
+import (
+	"testing"
+)
+
+func TestMergeNumMeasure(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("三 10 m\n个 10 q\n苹果 10 n\n十三亿 10 m\n人口 10 n\n买 10 v\n了 10 ul\n两 10 m\n张 10 nr\n票 10 n\n打 10 v\n")
+
+	segs := seg.Segment([]byte("买了三个苹果和5台电脑，两张票，十三亿人口"))
+	merged := MergeNumMeasure(segs)
+	expect(t, "买/v 了/ul 三个/m 苹果/n 和/x 5台/m 电/x 脑/x ，/w 两张/m 票/n ，/w 十三亿/m 人口/n ",
+		SegmentsToString(merged, false))
+	expect(t, "6", merged[2].Start())
+	expect(t, "12", merged[2].End())
+	expect(t, "2", merged[2].RuneStart())
+	expect(t, "4", merged[2].RuneEnd())
+
+	// 不改变输入
+	expect(t, "三/m ", SegmentsToString(segs[2:3], false))
+	expect(t, "0", len(MergeNumMeasure(nil)))
+}
+
+func TestSegmentQuantities(t *testing.T) {
+	dictionary := "三百 10 m\n块 10 q\n五 10 m\n公斤 10 q\n大米 10 n\n花 10 v\n了 10 ul\n买 10 v\n袋 10 n\n"
+	var seg Segmenter
+	seg.LoadDictionary(dictionary)
+
+	segs := seg.SegmentQuantities([]byte("花了三百块买五公斤大米，2袋"))
+	expect(t, "花/v 了/ul 三百块/m 买/v 五公斤/m 大米/n ，/w 2/m 袋/n ",
+		SegmentsToString(segs, false))
+	expect(t, "6", segs[2].Start())
+	expect(t, "15", segs[2].End())
+
+	// 原分词可以取回
+	components := segs[2].Components()
+	expect(t, "三百/m 块/q ", SegmentsToString(components, false))
+	expect(t, "6", components[0].Start())
+	expect(t, "12", components[1].Start())
+	expect(t, "0", len(segs[0].Components()))
+
+	// 自定义量词
+	custom := NewSegmenter(WithMeasureWords("袋"))
+	custom.LoadDictionary(dictionary)
+	expect(t, "花/v 了/ul 三百块/m 买/v 五公斤/m 大米/n ，/w 2袋/m ",
+		SegmentsToString(custom.SegmentQuantities([]byte("花了三百块买五公斤大米，2袋")), false))
+}
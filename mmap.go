@@ -0,0 +1,534 @@
+//go:build go1.17 && (linux || darwin || freebsd || netbsd || openbsd)
+// +build go1.17
+// +build linux darwin freebsd netbsd openbsd
+
+package sego
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"unsafe"
+
+	"github.com/adamzy/cedar-go"
+)
+
+// 编译后词典文件的格式
+//
+// 文件由一个固定长度的文件头和若干段组成，每段的起始位置按8字节对齐。所有数字
+// 使用本机的字节序和int长度，因此编译后的词典只能在相同架构的机器上使用。前缀
+// 树的三个数组按cedar的内存布局存放，载入时不做任何拷贝；载入和保存前都会检查
+// cedar的内存布局（见checkCedarLayout），布局改变时返回错误而不是读出错误的数据。
+const (
+	mmapMagic     = "SEGOMMAP"
+	mmapVersion   = 1
+	mmapByteOrder = 0x01020304
+)
+
+type mmapHeader struct {
+	Magic     [8]byte
+	Version   uint32
+	ByteOrder uint32
+	IntSize   uint32
+	_         uint32
+
+	TotalFrequency  int64
+	MaxTokenLength  int64
+	SearchMinLength int64
+
+	// 词典中没有的字元对应的伪分词的词性在字符串段中的位置，见SetUnknownPOS
+	UnknownPOSOff, UnknownPOSLen int64
+
+	// 各段的元素数和在文件中的起始位置
+	NumTokens, TokensOff int64
+	NumWords, WordsOff   int64
+	BlobLen, BlobOff     int64
+	ArrayLen, ArrayOff   int64
+	NinfosLen, NinfosOff int64
+	BlocksLen, BlocksOff int64
+
+	// cedar前缀树的其他字段
+	Reject                   [257]int64
+	BheadF, BheadC, BheadO   int64
+	Capacity, Size, MaxTrial int64
+	Ordered                  int64
+}
+
+// 分词在文件中的记录
+type mmapToken struct {
+	Frequency int64
+	Distance  float32
+	Forced    uint32
+	FirstWord uint32 // 第一个字元在字元长度段中的下标
+	NumWords  uint32
+	TextOff   uint64 // 文本在字符串段中的位置
+	PosOff    uint32 // 词性在字符串段中的位置
+	PosLen    uint32
+}
+
+// 和cedar内部的node、ninfo、block内存布局相同的结构体
+type (
+	cedarNode struct {
+		Value, Check int
+	}
+	cedarNinfo struct {
+		Sibling, Child byte
+	}
+	cedarBlock struct {
+		Prev, Next, Num, Reject, Trial, Ehead int
+	}
+)
+
+// cedar的内存布局和上面的结构体不同时返回的错误，在第一次使用时检查
+var (
+	cedarLayoutOnce sync.Once
+	cedarLayoutErr  error
+)
+
+// 检查cedar前缀树数组元素的内存布局是否和cedarNode等结构体相同
+//
+// cedar的这些类型没有导出，编译后的词典依赖它们的内存布局。升级cedar后布局
+// 改变时保存和载入编译后的词典都返回错误。
+func checkCedarLayout() error {
+	cedarLayoutOnce.Do(func() {
+		trie := reflect.TypeOf(cedar.Cedar{}).Field(0).Type.Elem()
+		for _, mirror := range []struct {
+			field string
+			local reflect.Type
+		}{
+			{"Array", reflect.TypeOf(cedarNode{})},
+			{"Ninfos", reflect.TypeOf(cedarNinfo{})},
+			{"Blocks", reflect.TypeOf(cedarBlock{})},
+		} {
+			field, ok := trie.FieldByName(mirror.field)
+			if !ok || field.Type.Kind() != reflect.Slice || !sameLayout(field.Type.Elem(), mirror.local) {
+				cedarLayoutErr = fmt.Errorf("sego: cedar前缀树的%s字段的内存布局和编译后的词典不兼容", mirror.field)
+				return
+			}
+		}
+	})
+	return cedarLayoutErr
+}
+
+// 判断两个结构体的字段名、类型和位置是否都相同
+func sameLayout(a, b reflect.Type) bool {
+	if a.Kind() != reflect.Struct || a.Size() != b.Size() || a.NumField() != b.NumField() {
+		return false
+	}
+	for i := 0; i < a.NumField(); i++ {
+		fa, fb := a.Field(i), b.Field(i)
+		if fa.Name != fb.Name || fa.Type != fb.Type || fa.Offset != fb.Offset {
+			return false
+		}
+	}
+	return true
+}
+
+// 内存映射的编译后词典中的分词数据
+//
+// 分词在第一次使用时才从映射的内存中读取（见Dictionary.token），分词文本直接
+// 引用映射的内存。
+type compiledTokens struct {
+	records []mmapToken
+	words   []uint32
+	blob    []byte
+
+	loaded     []uint32          // 每个分词是否已经读取，原子访问
+	lock       sync.Mutex        // 保证每个分词只读取一次
+	posStrings map[uint32]string // 相同的词性共享一个字符串
+
+	trieCopied bool // 前缀树的数组是否已经拷贝到堆上，见copyCompiledTrie
+}
+
+// 从映射的内存中读取第i个分词，已经读取过或者不是映射的分词时不做任何事
+func (c *compiledTokens) load(dict *Dictionary, i int) {
+	if i >= len(c.loaded) || atomic.LoadUint32(&c.loaded[i]) != 0 {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.loaded[i] != 0 {
+		return
+	}
+
+	record := &c.records[i]
+	token := &dict.tokens[i]
+	token.frequency = int(record.Frequency)
+	token.distance = record.Distance
+	token.forced = record.Forced != 0
+	token.dict = dict
+	posField, ok := c.posStrings[record.PosOff]
+	if !ok {
+		posField = string(c.blob[record.PosOff : record.PosOff+record.PosLen])
+		c.posStrings[record.PosOff] = posField
+	}
+	token.pos, token.posTags, _ = parsePOSField(posField)
+
+	token.text = make([]Text, record.NumWords)
+	offset := record.TextOff
+	for j := range token.text {
+		length := uint64(c.words[record.FirstWord+uint32(j)])
+		token.text[j] = c.blob[offset : offset+length : offset+length]
+		offset += length
+	}
+	atomic.StoreUint32(&c.loaded[i], 1)
+}
+
+// 修改映射的词典之前将前缀树的数组拷贝到堆上，映射的内存是只读的
+func (dict *Dictionary) copyCompiledTrie() {
+	if dict.compiled == nil || dict.compiled.trieCopied {
+		return
+	}
+	trie := dict.trie
+	array := (*[]cedarNode)(unsafe.Pointer(&trie.Array))
+	*array = append([]cedarNode(nil), *array...)
+	ninfos := (*[]cedarNinfo)(unsafe.Pointer(&trie.Ninfos))
+	*ninfos = append([]cedarNinfo(nil), *ninfos...)
+	blocks := (*[]cedarBlock)(unsafe.Pointer(&trie.Blocks))
+	*blocks = append([]cedarBlock(nil), *blocks...)
+	dict.compiled.trieCopied = true
+}
+
+// 将编译后的词典保存到文件，见LoadMmapDictionary
+//
+// 保存的内容包括分词、路径值和前缀树，不包括异体字映射表、拼音表和同义词表。
+// 编译后的词典只能在相同架构（字节序和int长度）的机器上载入。
+func (dict *Dictionary) SaveCompiled(path string) error {
+	if err := checkCedarLayout(); err != nil {
+		return err
+	}
+	dict.rebuildIfDirty()
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(file)
+	if err := dict.writeCompiled(writer); err != nil {
+		file.Close()
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func (dict *Dictionary) writeCompiled(writer *bufio.Writer) error {
+	// 整理分词、字元长度和字符串
+	records := make([]mmapToken, len(dict.tokens))
+	var words []uint32
+	var blob []byte
+	posOffsets := make(map[string]uint32)
+	for i := range dict.tokens {
+		token := dict.token(i)
+		record := &records[i]
+		record.Frequency = int64(token.frequency)
+		record.Distance = token.distance
+		if token.forced {
+			record.Forced = 1
+		}
+		record.FirstWord = uint32(len(words))
+		record.NumWords = uint32(len(token.text))
+		record.TextOff = uint64(len(blob))
+		for _, word := range token.text {
+			words = append(words, uint32(len(word)))
+			blob = append(blob, word...)
+		}
+		posField := formatPOSField(token)
+		offset, ok := posOffsets[posField]
+		if !ok {
+			offset = uint32(len(blob))
+			posOffsets[posField] = offset
+			blob = append(blob, posField...)
+		}
+		record.PosOff = offset
+		record.PosLen = uint32(len(posField))
+	}
+	unknownPOSOff := int64(len(blob))
+	blob = append(blob, dict.unknownPOS...)
+
+	trie := dict.trie
+	array := *(*[]cedarNode)(unsafe.Pointer(&trie.Array))
+	ninfos := *(*[]cedarNinfo)(unsafe.Pointer(&trie.Ninfos))
+	blocks := *(*[]cedarBlock)(unsafe.Pointer(&trie.Blocks))
+
+	header := mmapHeader{
+		Version:         mmapVersion,
+		ByteOrder:       mmapByteOrder,
+		IntSize:         uint32(unsafe.Sizeof(int(0))),
+		TotalFrequency:  dict.totalFrequency,
+		MaxTokenLength:  int64(dict.maxTokenLength),
+		SearchMinLength: int64(dict.searchMinLength),
+		UnknownPOSOff:   unknownPOSOff,
+		UnknownPOSLen:   int64(len(dict.unknownPOS)),
+		NumTokens:       int64(len(records)),
+		NumWords:        int64(len(words)),
+		BlobLen:         int64(len(blob)),
+		ArrayLen:        int64(len(array)),
+		NinfosLen:       int64(len(ninfos)),
+		BlocksLen:       int64(len(blocks)),
+		BheadF:          int64(trie.BheadF),
+		BheadC:          int64(trie.BheadC),
+		BheadO:          int64(trie.BheadO),
+		Capacity:        int64(trie.Capacity),
+		Size:            int64(trie.Size),
+		MaxTrial:        int64(trie.MaxTrial),
+	}
+	copy(header.Magic[:], mmapMagic)
+	for i, reject := range trie.Reject {
+		header.Reject[i] = int64(reject)
+	}
+	if trie.Ordered {
+		header.Ordered = 1
+	}
+
+	// 依次排列各段
+	sections := []struct {
+		offset *int64
+		data   []byte
+	}{
+		{&header.TokensOff, sliceBytes(unsafe.Pointer(&records), len(records), unsafe.Sizeof(mmapToken{}))},
+		{&header.WordsOff, sliceBytes(unsafe.Pointer(&words), len(words), 4)},
+		{&header.BlobOff, blob},
+		{&header.ArrayOff, sliceBytes(unsafe.Pointer(&array), len(array), unsafe.Sizeof(cedarNode{}))},
+		{&header.NinfosOff, sliceBytes(unsafe.Pointer(&ninfos), len(ninfos), unsafe.Sizeof(cedarNinfo{}))},
+		{&header.BlocksOff, sliceBytes(unsafe.Pointer(&blocks), len(blocks), unsafe.Sizeof(cedarBlock{}))},
+	}
+	offset := alignOffset(int64(unsafe.Sizeof(header)))
+	for _, section := range sections {
+		*section.offset = offset
+		offset = alignOffset(offset + int64(len(section.data)))
+	}
+
+	headerBytes := (*[unsafe.Sizeof(mmapHeader{})]byte)(unsafe.Pointer(&header))[:]
+	written := int64(0)
+	write := func(data []byte) error {
+		padding := make([]byte, alignOffset(written)-written)
+		if _, err := writer.Write(padding); err != nil {
+			return err
+		}
+		_, err := writer.Write(data)
+		written += int64(len(padding) + len(data))
+		return err
+	}
+	if err := write(headerBytes); err != nil {
+		return err
+	}
+	for _, section := range sections {
+		if err := write(section.data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 从编译后的词典文件（见Dictionary.SaveCompiled）创建分词器，文件以只读的内存
+// 映射方式载入
+//
+// 前缀树、分词记录和分词文本直接引用映射的内存而不拷贝到堆上，多个进程载入同一
+// 个文件时共享这些只读的内存页。载入时只检查文件中的各个位置和长度是否合法，
+// 分词在第一次用到时才读取，因此载入的代价远小于LoadDictionary。文件不完整或
+// 损坏时返回错误。之后用AddToken等函数修改词典时前缀树先被拷贝到堆上，不会
+// 改变文件。
+//
+// 不再使用时调用Segmenter.Close解除映射。解除映射后分词器以及之前返回的分词
+// 结果中的分词都不能再使用，否则会访问非法内存。
+func LoadMmapDictionary(path string) (*Segmenter, error) {
+	if err := checkCedarLayout(); err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() < int64(unsafe.Sizeof(mmapHeader{})) {
+		return nil, errors.New("sego: 编译后的词典文件太短")
+	}
+	data, err := syscall.Mmap(int(file.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, err
+	}
+
+	dict, err := compiledDictionary(data)
+	if err != nil {
+		syscall.Munmap(data)
+		return nil, err
+	}
+	dict.mapped = data
+	seg := NewSegmenter()
+	seg.dict = dict
+	seg.searchMinLength = dict.searchMinLength
+	seg.unknownPOS = dict.unknownPOS
+	return seg, nil
+}
+
+// 词典文件损坏时返回的错误
+func errCorruptCompiled(what string) error {
+	return fmt.Errorf("sego: 编译后的词典文件损坏（%s）", what)
+}
+
+// 检查data中offset处的count个大小为size的元素是否在data范围内且按8字节对齐
+func checkSection(data []byte, offset, count int64, size uintptr, what string) error {
+	headerSize := int64(unsafe.Sizeof(mmapHeader{}))
+	if offset < headerSize || offset%8 != 0 || offset > int64(len(data)) || count < 0 ||
+		count > (int64(len(data))-offset)/int64(size) {
+		return errCorruptCompiled(what)
+	}
+	return nil
+}
+
+// 从编译后的词典数据创建词典，词典引用data而不拷贝
+//
+// 文件中所有的位置和长度都先检查是否在data的范围内，之后按位置读取时不会越界。
+func compiledDictionary(data []byte) (*Dictionary, error) {
+	header := (*mmapHeader)(unsafe.Pointer(&data[0]))
+	if string(header.Magic[:]) != mmapMagic {
+		return nil, errors.New("sego: 不是编译后的词典文件")
+	}
+	if header.Version != mmapVersion || header.ByteOrder != mmapByteOrder ||
+		header.IntSize != uint32(unsafe.Sizeof(int(0))) {
+		return nil, errors.New("sego: 编译后的词典文件的版本或架构不匹配")
+	}
+	for _, section := range []struct {
+		offset, count int64
+		size          uintptr
+		what          string
+	}{
+		{header.TokensOff, header.NumTokens, unsafe.Sizeof(mmapToken{}), "分词段"},
+		{header.WordsOff, header.NumWords, 4, "字元长度段"},
+		{header.BlobOff, header.BlobLen, 1, "字符串段"},
+		{header.ArrayOff, header.ArrayLen, unsafe.Sizeof(cedarNode{}), "前缀树Array段"},
+		{header.NinfosOff, header.NinfosLen, unsafe.Sizeof(cedarNinfo{}), "前缀树Ninfos段"},
+		{header.BlocksOff, header.BlocksLen, unsafe.Sizeof(cedarBlock{}), "前缀树Blocks段"},
+	} {
+		if err := checkSection(data, section.offset, section.count, section.size, section.what); err != nil {
+			return nil, err
+		}
+	}
+	if header.NumTokens > math.MaxInt32 || header.ArrayLen == 0 {
+		return nil, errCorruptCompiled("文件头")
+	}
+
+	records := unsafe.Slice((*mmapToken)(pointerAt(data, header.TokensOff)), header.NumTokens)
+	words := unsafe.Slice((*uint32)(pointerAt(data, header.WordsOff)), header.NumWords)
+	blob := data[header.BlobOff : header.BlobOff+header.BlobLen : header.BlobOff+header.BlobLen]
+	array := unsafe.Slice((*cedarNode)(pointerAt(data, header.ArrayOff)), header.ArrayLen)
+
+	if header.UnknownPOSOff < 0 || header.UnknownPOSLen < 0 ||
+		header.UnknownPOSOff > header.BlobLen || header.UnknownPOSLen > header.BlobLen-header.UnknownPOSOff {
+		return nil, errCorruptCompiled("未知词性")
+	}
+
+	// 每个分词记录的字元和字符串位置
+	maxTokenLength := int64(0)
+	for i := range records {
+		record := &records[i]
+		if uint64(record.FirstWord)+uint64(record.NumWords) > uint64(header.NumWords) ||
+			uint64(record.PosOff)+uint64(record.PosLen) > uint64(header.BlobLen) {
+			return nil, errCorruptCompiled(fmt.Sprintf("第%d个分词", i))
+		}
+		end := record.TextOff
+		for _, length := range words[record.FirstWord : record.FirstWord+record.NumWords] {
+			end += uint64(length)
+		}
+		if record.TextOff > uint64(header.BlobLen) || end > uint64(header.BlobLen) || end < record.TextOff {
+			return nil, errCorruptCompiled(fmt.Sprintf("第%d个分词", i))
+		}
+		if int64(record.NumWords) > maxTokenLength {
+			maxTokenLength = int64(record.NumWords)
+		}
+	}
+	if header.MaxTokenLength != maxTokenLength {
+		return nil, errCorruptCompiled("文件头")
+	}
+
+	// 前缀树中的值是分词的下标，内部节点的子节点都在数组范围内
+	for i := range array {
+		node := &array[i]
+		if node.Check < 0 {
+			continue
+		}
+		if node.Value >= int(header.NumTokens) ||
+			(node.Value < 0 && -(node.Value+1)|0xFF >= len(array)) {
+			return nil, errCorruptCompiled("前缀树")
+		}
+	}
+
+	dict := NewDictionary()
+	dict.totalFrequency = header.TotalFrequency
+	dict.maxTokenLength = int(header.MaxTokenLength)
+	dict.searchMinLength = int(header.SearchMinLength)
+	dict.unknownPOS = string(blob[header.UnknownPOSOff : header.UnknownPOSOff+header.UnknownPOSLen])
+
+	// 前缀树的数组直接引用映射的内存
+	trie := dict.trie
+	*(*[]cedarNode)(unsafe.Pointer(&trie.Array)) = array
+	*(*[]cedarNinfo)(unsafe.Pointer(&trie.Ninfos)) =
+		unsafe.Slice((*cedarNinfo)(pointerAt(data, header.NinfosOff)), header.NinfosLen)
+	*(*[]cedarBlock)(unsafe.Pointer(&trie.Blocks)) =
+		unsafe.Slice((*cedarBlock)(pointerAt(data, header.BlocksOff)), header.BlocksLen)
+	for i := range trie.Reject {
+		trie.Reject[i] = int(header.Reject[i])
+	}
+	trie.BheadF = int(header.BheadF)
+	trie.BheadC = int(header.BheadC)
+	trie.BheadO = int(header.BheadO)
+	trie.Capacity = int(header.Capacity)
+	trie.Size = int(header.Size)
+	trie.MaxTrial = int(header.MaxTrial)
+	trie.Ordered = header.Ordered != 0
+
+	// 分词在第一次使用时才读取，这里只分配分词数组，子分词在第一次使用时构建
+	dict.tokens = make([]Token, len(records))
+	dict.compiled = &compiledTokens{
+		records:    records,
+		words:      words,
+		blob:       blob,
+		loaded:     make([]uint32, len(records)),
+		posStrings: make(map[uint32]string),
+	}
+	return dict, nil
+}
+
+// 解除词典的内存映射
+func (dict *Dictionary) unmap() {
+	if dict.mapped != nil {
+		syscall.Munmap(dict.mapped)
+		dict.mapped = nil
+	}
+	dict.compiled = nil
+}
+
+// 返回切片的底层字节，slice为指向切片的指针
+func sliceBytes(slice unsafe.Pointer, length int, elemSize uintptr) []byte {
+	if length == 0 {
+		return nil
+	}
+	data := *(*unsafe.Pointer)(slice)
+	return unsafe.Slice((*byte)(data), uintptr(length)*elemSize)
+}
+
+// 返回data中offset处的指针，offset等于len(data)时（空段）返回data的起始位置
+func pointerAt(data []byte, offset int64) unsafe.Pointer {
+	if offset >= int64(len(data)) {
+		return unsafe.Pointer(&data[0])
+	}
+	return unsafe.Pointer(&data[offset])
+}
+
+// 向上对齐到8字节
+func alignOffset(offset int64) int64 {
+	return (offset + 7) &^ 7
+}
@@ -0,0 +1,32 @@
+//go:build !go1.17 || !(linux || darwin || freebsd || netbsd || openbsd)
+// +build !go1.17 !linux,!darwin,!freebsd,!netbsd,!openbsd
+
+package sego
+
+import (
+	"errors"
+)
+
+var errMmapUnsupported = errors.New("sego: 当前平台不支持内存映射词典")
+
+// 将编译后的词典保存到文件，当前平台不支持，总是返回错误
+func (dict *Dictionary) SaveCompiled(path string) error {
+	return errMmapUnsupported
+}
+
+// 从编译后的词典文件创建分词器，当前平台不支持，总是返回错误
+func LoadMmapDictionary(path string) (*Segmenter, error) {
+	return nil, errMmapUnsupported
+}
+
+// 解除词典的内存映射，当前平台没有映射，不做任何事
+func (dict *Dictionary) unmap() {}
+
+// 内存映射的编译后词典中的分词数据，当前平台不支持，总是为nil
+type compiledTokens struct{}
+
+// 从映射的内存中读取分词，当前平台没有映射，不做任何事
+func (c *compiledTokens) load(dict *Dictionary, i int) {}
+
+// 修改映射的词典之前拷贝前缀树，当前平台没有映射，不做任何事
+func (dict *Dictionary) copyCompiledTrie() {}
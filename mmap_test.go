@@ -0,0 +1,146 @@
+//go:build go1.17 && (linux || darwin || freebsd || netbsd || openbsd)
+// +build go1.17
+// +build linux darwin freebsd netbsd openbsd
+
+package sego
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+func TestLoadMmapDictionary(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中华 10 nz\n人民 10 n\n共和国 10 ns\n中华人民共和国 10 ns\n万岁 5 i:0.8,v:0.2\nGitHub 3 nz\n")
+	seg.ForceWord("国万", "x")
+	path := filepath.Join(t.TempDir(), "dictionary.bin")
+	expect(t, "<nil>", seg.dict.SaveCompiled(path))
+
+	mapped, err := LoadMmapDictionary(path)
+	expect(t, "<nil>", err)
+	expect(t, "7", mapped.dict.NumTokens())
+	// 分词在第一次使用时才读取
+	expect(t, "[0 0 0 0 0 0 0]", mapped.dict.compiled.loaded)
+	expect(t, fmt.Sprint(seg.dict.TotalFrequency()), mapped.dict.TotalFrequency())
+	expect(t, "7", mapped.dict.MaxTokenLength())
+
+	for _, text := range []string{"中华人民共和国万岁", "GitHub上的中华人民共和国", "人民万岁\xff"} {
+		expect(t, SegmentsToString(seg.Segment([]byte(text)), false),
+			SegmentsToString(mapped.Segment([]byte(text)), false))
+		expect(t, SegmentsToString(seg.InternalSegment([]byte(text), true), true),
+			SegmentsToString(mapped.InternalSegment([]byte(text), true), true))
+	}
+	expect(t, "中华/nz 中华人民共和国/ns ", tokensToString(mapped.dict.LookupPrefix("中华")))
+	expect(t, "true", mapped.dict.token(6).forced)
+	expect(t, "[{i 0.8} {v 0.2}]", mapped.dict.token(4).POSTags())
+	expect(t, fmt.Sprintf("%x", seg.dict.Checksum()), fmt.Sprintf("%x", mapped.dict.Checksum()))
+
+	// 修改映射的词典不影响文件
+	mapped.AddToken("人民共和国", 100, "n")
+	expect(t, "人民共和国/n ", SegmentsToString(mapped.Segment([]byte("人民共和国")), false))
+	mapped.Close()
+
+	mapped, err = LoadMmapDictionary(path)
+	expect(t, "<nil>", err)
+	expect(t, "7", mapped.dict.NumTokens())
+	expect(t, "人民/n 共和国/ns ", SegmentsToString(mapped.Segment([]byte("人民共和国")), false))
+	dict := mapped.dict
+	mapped.Close()
+	expect(t, "true", dict.mapped == nil)
+}
+
+func TestLoadMmapDictionaryErrors(t *testing.T) {
+	dir := t.TempDir()
+	_, err := LoadMmapDictionary(filepath.Join(dir, "not_exist.bin"))
+	expect(t, "true", err != nil)
+
+	path := filepath.Join(dir, "short.bin")
+	os.WriteFile(path, []byte("SEGOMMAP"), 0644)
+	_, err = LoadMmapDictionary(path)
+	expect(t, "sego: 编译后的词典文件太短", err)
+
+	path = filepath.Join(dir, "text.bin")
+	os.WriteFile(path, make([]byte, 4096), 0644)
+	_, err = LoadMmapDictionary(path)
+	expect(t, "sego: 不是编译后的词典文件", err)
+
+	// 截断或者损坏的文件返回错误而不是在读取时崩溃
+	var seg Segmenter
+	seg.LoadDictionary("中华 10 nz\n人民 10 n\n共和国 10 ns\n中华人民共和国 10 ns\n")
+	path = filepath.Join(dir, "dictionary.bin")
+	expect(t, "<nil>", seg.dict.SaveCompiled(path))
+	data, _ := os.ReadFile(path)
+	header := *(*mmapHeader)(unsafe.Pointer(&data[0]))
+	for _, length := range []int64{int64(unsafe.Sizeof(header)), header.WordsOff, header.BlobOff + 1, int64(len(data) - 1)} {
+		os.WriteFile(path, data[:length], 0644)
+		_, err = LoadMmapDictionary(path)
+		expect(t, "true", err != nil)
+	}
+
+	corrupt := func(offset int64, value uint64) error {
+		damaged := append([]byte(nil), data...)
+		*(*uint64)(unsafe.Pointer(&damaged[offset])) = value
+		os.WriteFile(path, damaged, 0644)
+		_, err := LoadMmapDictionary(path)
+		return err
+	}
+	record := header.TokensOff + int64(unsafe.Sizeof(mmapToken{}))
+	expect(t, "sego: 编译后的词典文件损坏（第1个分词）",
+		corrupt(record+int64(unsafe.Offsetof(mmapToken{}.TextOff)), 1<<40))
+	expect(t, "sego: 编译后的词典文件损坏（第1个分词）",
+		corrupt(record+int64(unsafe.Offsetof(mmapToken{}.FirstWord)), 1<<20|2))
+	expect(t, "sego: 编译后的词典文件损坏（分词段）",
+		corrupt(int64(unsafe.Offsetof(header.TokensOff)), 1<<40))
+	expect(t, "sego: 编译后的词典文件损坏（字元长度段）",
+		corrupt(int64(unsafe.Offsetof(header.WordsOff)), uint64(header.WordsOff+1)))
+	expect(t, "sego: 编译后的词典文件损坏（前缀树）",
+		corrupt(header.ArrayOff, 1<<40))
+}
+
+func TestLoadMmapDictionaryUnknownPOS(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n")
+	seg.SetUnknownPOS("unk")
+	path := filepath.Join(t.TempDir(), "dictionary.bin")
+	expect(t, "<nil>", seg.dict.SaveCompiled(path))
+
+	mapped, err := LoadMmapDictionary(path)
+	expect(t, "<nil>", err)
+	defer mapped.Close()
+	expect(t, SegmentsToString(seg.Segment([]byte("中国有人")), false),
+		SegmentsToString(mapped.Segment([]byte("中国有人")), false))
+	expect(t, "中国/ns 有/unk ", SegmentsToString(mapped.Segment([]byte("中国有")), false))
+}
+
+func TestLoadMmapDictionaryConcurrent(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中华 10 nz\n人民 10 n\n共和国 10 ns\n中华人民共和国 10 ns\n万岁 5 i\n")
+	path := filepath.Join(t.TempDir(), "dictionary.bin")
+	expect(t, "<nil>", seg.dict.SaveCompiled(path))
+	mapped, err := LoadMmapDictionary(path)
+	expect(t, "<nil>", err)
+	defer mapped.Close()
+
+	// 多个goroutine同时第一次用到同一个分词
+	text := []byte("中华人民共和国万岁")
+	expected := SegmentsToString(seg.Segment(text), true)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			expect(t, expected, SegmentsToString(mapped.Segment(text), true))
+		}()
+	}
+	wg.Wait()
+}
+
+func TestCedarLayout(t *testing.T) {
+	expect(t, "<nil>", checkCedarLayout())
+	expect(t, "false", sameLayout(reflect.TypeOf(cedarNode{}), reflect.TypeOf(cedarBlock{})))
+}
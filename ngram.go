@@ -0,0 +1,34 @@
+package sego
+
+// 返回分词结果中所有连续的n个分词，比如n为2时返回所有相邻的两个分词
+//
+// 返回的每个n元组是segs的子切片（容量限制为n，向其追加不会改变segs）。n小于等于
+// 零或者大于分词数时返回nil。
+func NGrams(segs []Segment, n int) [][]Segment {
+	if n <= 0 || n > len(segs) {
+		return nil
+	}
+	output := make([][]Segment, len(segs)-n+1)
+	for i := range output {
+		output[i] = segs[i : i+n : i+n]
+	}
+	return output
+}
+
+// 统计分词结果中每个词出现的次数，以分词文本为键
+func UnigramFreq(segs []Segment) map[string]int {
+	freq := make(map[string]int)
+	for _, s := range segs {
+		freq[s.token.Text()]++
+	}
+	return freq
+}
+
+// 统计分词结果中每对相邻的词出现的次数，以两个分词的文本为键
+func BigramFreq(segs []Segment) map[[2]string]int {
+	freq := make(map[[2]string]int)
+	for i := 1; i < len(segs); i++ {
+		freq[[2]string{segs[i-1].token.Text(), segs[i].token.Text()}]++
+	}
+	return freq
+}
@@ -0,0 +1,36 @@
+package sego
+
+import (
+	"testing"
+)
+
+func TestNGrams(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n众多 10 a\n")
+	segments := seg.Segment([]byte("中国人口众多"))
+
+	bigrams := NGrams(segments, 2)
+	expect(t, "2", len(bigrams))
+	expect(t, "中国/ns 人口/n ", SegmentsToString(bigrams[0], false))
+	expect(t, "人口/n 众多/a ", SegmentsToString(bigrams[1], false))
+	expect(t, "2", cap(bigrams[0]))
+
+	expect(t, "3", len(NGrams(segments, 1)))
+	expect(t, "中国/ns 人口/n 众多/a ", SegmentsToString(NGrams(segments, 3)[0], false))
+	expect(t, "[]", NGrams(segments, 4))
+	expect(t, "[]", NGrams(segments, 0))
+}
+
+func TestUnigramAndBigramFreq(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n")
+	segments := seg.Segment([]byte("中国人口，中国人口"))
+
+	expect(t, "map[中国:2 人口:2 ，:1]", UnigramFreq(segments))
+	bigrams := BigramFreq(segments)
+	expect(t, "3", len(bigrams))
+	expect(t, "2", bigrams[[2]string{"中国", "人口"}])
+	expect(t, "1", bigrams[[2]string{"人口", "，"}])
+	expect(t, "1", bigrams[[2]string{"，", "中国"}])
+	expect(t, "0", len(BigramFreq(segments[:1])))
+}
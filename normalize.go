@@ -0,0 +1,208 @@
+package sego
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// 分词前对输入文本做Unicode规范化，form可以是norm.NFC、norm.NFD、norm.NFKC
+// 或norm.NFKD
+//
+// 同一个字在不同来源的文本中可能有不同的编码形式，比如"é"可以是一个字符
+// U+00E9，也可以是"e"加上组合字符U+0301，规范化之后它们会得到相同的分词。
+// 载入词典时词典中的分词也会被同样规范化。
+//
+// 规范化可能改变文本的字节长度，分词位置（包括字符位置）仍然是相对于原文本
+// 的，对应规范化之前的字符；分词文本是规范化之后的文本。
+func WithUnicodeNormalization(form norm.Form) Option {
+	return func(seg *Segmenter) {
+		seg.unicodeNormalization = true
+		seg.normForm = form
+	}
+}
+
+// 分词前将全角字母（Ａ－Ｚ、ａ－ｚ）和数字（０－９）转换为对应的半角字符
+//
+// 这样"ＡＢＣ"和"ABC"会被同样地当作英文词处理。全角标点不做转换。载入词典时
+// 词典中的分词也会被同样转换。分词位置仍然是相对于原文本的，分词文本是转换
+// 之后的半角文本。
+func WithFullWidthNormalization() Option {
+	return func(seg *Segmenter) {
+		seg.fullWidthNormalization = true
+	}
+}
+
+// 分词前按词典的简繁异体字映射表（见Dictionary.LoadVariantMap）将输入中的
+// 异体字转换为规范字
+//
+// 映射表保证异体字和规范字的UTF8编码长度相同，因此分词位置对应原文本。
+// 匹配到词典分词时返回的是词典中的分词文本，可能和原文本的简繁形式不同。
+// 词典没有载入映射表时该选项不起作用。
+func WithVariantNormalization() Option {
+	return func(seg *Segmenter) {
+		seg.variantNormalization = true
+	}
+}
+
+// 划分字元时用unicode.ToLower将非ASCII字母也转换为小写
+//
+// 默认只转换ASCII字母A－Z，"Café"和"CAFÉ"得到不同的字元；启用后带变音符号的
+// 拉丁字母、希腊字母等也转换为小写，两者都匹配词典中的"café"。为了保证分词
+// 位置对应原文本，小写形式的UTF8编码长度和原字符不同的字符（比如"İ"）保持不变。
+// 载入词典时词典中的分词也会被同样转换，因此需要在载入词典之前设置。
+func WithUnicodeLowercase() Option {
+	return func(seg *Segmenter) {
+		seg.unicodeLowercase = true
+	}
+}
+
+// 将字元中的非ASCII字母也转换为小写，没有需要转换的字符时直接返回输入
+//
+// 只转换小写形式的UTF8编码长度和原字符相同的字符，见WithUnicodeLowercase。
+func toLowerUnicode(word []byte) []byte {
+	var output []byte
+	for current := 0; current < len(word); {
+		r, size := utf8.DecodeRune(word[current:])
+		lower := unicode.ToLower(r)
+		if lower != r && utf8.RuneLen(lower) == size {
+			if output == nil {
+				output = make([]byte, current, len(word))
+				copy(output, word[:current])
+			}
+			var buf [utf8.UTFMax]byte
+			output = append(output, buf[:utf8.EncodeRune(buf[:], lower)]...)
+		} else if output != nil {
+			output = append(output, word[current:current+size]...)
+		}
+		current += size
+	}
+	if output == nil {
+		return word
+	}
+	return output
+}
+
+// 按分词器的选项对文本做分词前的规范化，未启用任何规范化时直接返回输入
+func (seg *Segmenter) normalizeText(text []byte) []byte {
+	if seg.unicodeNormalization {
+		text = seg.normForm.Bytes(text)
+	}
+	if seg.fullWidthNormalization {
+		text = fullWidthToHalfWidth(text)
+	}
+	if seg.variantNormalization && seg.dict != nil && seg.dict.variants != nil {
+		text = seg.dict.mapVariants(text)
+	}
+	return text
+}
+
+// 转换之后的文本中的一个字节在原文本中对应的字节区间
+type textSpan struct {
+	start, end int
+}
+
+// 和normalizeText相同，同时返回规范化之后的文本中每个字节在原文本中对应的
+// 字节区间，用于把分词位置转换回原文本（见remapSegments）
+//
+// 规范化没有改变字符的编码长度时（比如只做了异体字转换）返回的区间为nil，
+// 分词位置不需要转换。
+func (seg *Segmenter) normalizeTextWithSpans(text []byte) ([]byte, []textSpan) {
+	normalized := seg.normalizeText(text)
+	// 全角转换总是缩短文本，长度不变并且不需要Unicode规范化时位置一一对应
+	if len(normalized) == len(text) && (!seg.unicodeNormalization || seg.normForm.IsNormal(text)) {
+		return normalized, nil
+	}
+
+	// 逐个规范化单位（没有Unicode规范化时为一个字符）转换，单位中的每个字节都
+	// 对应原文本中的整个单位
+	output := make([]byte, 0, len(normalized))
+	spans := make([]textSpan, 0, len(normalized))
+	var iter norm.Iter
+	if seg.unicodeNormalization {
+		iter.Init(seg.normForm, text)
+	}
+	for current := 0; current < len(text); {
+		var chunk []byte
+		var next int
+		if seg.unicodeNormalization {
+			chunk = iter.Next()
+			next = iter.Pos()
+		} else {
+			_, size := utf8.DecodeRune(text[current:])
+			chunk = text[current : current+size]
+			next = current + size
+		}
+		if seg.fullWidthNormalization {
+			chunk = fullWidthToHalfWidth(chunk)
+		}
+		for range chunk {
+			spans = append(spans, textSpan{start: current, end: next})
+		}
+		output = append(output, chunk...)
+		current = next
+	}
+	if seg.variantNormalization && seg.dict != nil && seg.dict.variants != nil {
+		output = seg.dict.mapVariants(output)
+	}
+	return output, spans
+}
+
+// 将相对于转换之后的文本的分词位置转换为相对于原文本src的位置
+//
+// spans为转换之后的文本中每个字节在src中对应的字节区间，区间的start为负时
+// 该字节在src中没有对应（比如SegmentHTML中块级标签产生的分隔符），起始于这样
+// 的字节的分词被去掉。字符位置按src重新计算，结果原地写入segments。
+func remapSegments(segments []Segment, spans []textSpan, src []byte) []Segment {
+	output := segments[:0]
+	runePosition := 0
+	bytePosition := 0
+	for _, s := range segments {
+		if spans[s.start].start < 0 {
+			continue
+		}
+		s.start = spans[s.start].start
+		s.end = spans[s.end-1].end
+		if s.start < bytePosition {
+			// 和前一个分词对应原文本中的同一个规范化单位
+			runePosition, bytePosition = 0, 0
+		}
+
+		runePosition += utf8.RuneCount(src[bytePosition:s.start])
+		s.runeStart = runePosition
+		runePosition += utf8.RuneCount(src[s.start:s.end])
+		s.runeEnd = runePosition
+		bytePosition = s.end
+
+		output = append(output, s)
+	}
+	return output
+}
+
+// 将全角字母和数字转换为半角，没有需要转换的字符时直接返回输入
+func fullWidthToHalfWidth(text []byte) []byte {
+	var output []byte
+	for current := 0; current < len(text); {
+		r, size := utf8.DecodeRune(text[current:])
+		if isFullWidthAlphanumeric(r) {
+			if output == nil {
+				output = make([]byte, current, len(text))
+				copy(output, text[:current])
+			}
+			output = append(output, byte(r-'０'+'0'))
+		} else if output != nil {
+			output = append(output, text[current:current+size]...)
+		}
+		current += size
+	}
+	if output == nil {
+		return text
+	}
+	return output
+}
+
+// 判断是否为全角字母或数字
+func isFullWidthAlphanumeric(r rune) bool {
+	return (r >= '０' && r <= '９') || (r >= 'Ａ' && r <= 'Ｚ') || (r >= 'ａ' && r <= 'ｚ')
+}
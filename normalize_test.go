@@ -0,0 +1,109 @@
+package sego
+
+import (
+	"testing"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+func TestUnicodeNormalization(t *testing.T) {
+	dictionary := "café 10 n\n豈 10 n\n"
+	// "e"加组合字符U+0301，以及兼容汉字U+F900
+	text := []byte("café豈")
+
+	var seg Segmenter
+	seg.LoadDictionary(dictionary)
+	expect(t, "cafe/x ́/x 豈/x ", SegmentsToString(seg.Segment(text), false))
+
+	nfcSeg := NewSegmenter(WithUnicodeNormalization(norm.NFC))
+	nfcSeg.LoadDictionary(dictionary)
+	segments := nfcSeg.Segment(text)
+	expect(t, "café/n 豈/n ", SegmentsToString(segments, false))
+	// 位置相对于原文本
+	expect(t, "6", segments[0].end)
+	expect(t, "9", segments[1].end)
+	expect(t, "5", segments[0].runeEnd)
+	expect(t, "6", segments[1].runeEnd)
+	expect(t, "cafe\u0301", segments[0].String(text))
+	expect(t, "\uf900", segments[1].String(text))
+
+	// 词典中的分词也被规范化
+	nfdSeg := NewSegmenter(WithUnicodeNormalization(norm.NFD))
+	nfdSeg.LoadDictionary(dictionary)
+	segments = nfdSeg.Segment([]byte("café"))
+	expect(t, "café/n ", SegmentsToString(segments, false))
+}
+
+func TestFullWidthNormalization(t *testing.T) {
+	dictionary := "iphone 10 nz\n手机 10 n\n"
+	text := []byte("ＩＰｈｏｎｅ手机１２３，")
+
+	var seg Segmenter
+	seg.LoadDictionary(dictionary)
+	expect(t, "Ｉ/x Ｐ/x ｈ/x ｏ/x ｎ/x ｅ/x 手机/n １/m ２/m ３/m ，/w ",
+		SegmentsToString(seg.Segment(text), false))
+
+	fwSeg := NewSegmenter(WithFullWidthNormalization())
+	fwSeg.LoadDictionary(dictionary)
+	segments := fwSeg.Segment(text)
+	expect(t, "iphone/nz 手机/n 123/m ，/w ", SegmentsToString(segments, false))
+	// 位置相对于原文本
+	expect(t, "18", segments[1].start)
+	expect(t, "24", segments[1].end)
+	expect(t, "33", segments[2].end)
+	expect(t, "8", segments[2].runeStart)
+	expect(t, "11", segments[2].runeEnd)
+	expect(t, "ＩＰｈｏｎｅ", segments[0].String(text))
+	expect(t, "１２３", segments[2].String(text))
+	expect(t, "ＩＰｈｏｎｅ手机<b>１２３</b>，", Highlight(text, []string{"123"}, fwSeg, "<b>", "</b>"))
+
+	// 词典中的全角分词也被转换
+	fwSeg.LoadDictionary("ＧＰＵ 10 nz\n")
+	expect(t, "gpu/nz ", SegmentsToString(fwSeg.Segment([]byte("GPU")), false))
+
+	// 没有需要转换的字符时不拷贝
+	halfWidth := []byte("abc中文")
+	expect(t, "true", &fullWidthToHalfWidth(halfWidth)[0] == &halfWidth[0])
+}
+
+func TestVariantNormalization(t *testing.T) {
+	seg := NewSegmenter(WithVariantNormalization())
+	seg.LoadDictionary("中国 10 ns\n發展 10 vn\n台湾 10 ns\n")
+	expect(t, "<nil>", seg.dict.LoadVariantMap("testdata/test_variants.txt"))
+
+	// 简体词典匹配繁体输入，繁体词典匹配简体输入
+	text := []byte("中國发展臺灣")
+	segments := seg.Segment(text)
+	expect(t, "中国/ns 發展/vn 台湾/ns ", SegmentsToString(segments, false))
+	expect(t, "6", segments[0].end)
+	expect(t, "12", segments[1].end)
+	expect(t, "18", segments[2].end)
+
+	// 未启用选项时不做转换
+	var plain Segmenter
+	plain.LoadDictionary("中国 10 ns\n")
+	expect(t, "<nil>", plain.dict.LoadVariantMap("testdata/test_variants.txt"))
+	expect(t, "中/x 國/x ", SegmentsToString(plain.Segment([]byte("中國")), false))
+
+	expect(t, "true", seg.dict.LoadVariantMap("testdata/not_exist.txt") != nil)
+}
+
+func TestUnicodeLowercase(t *testing.T) {
+	// 默认只转换ASCII字母
+	var seg Segmenter
+	seg.LoadDictionary("Café 10 n\n")
+	expect(t, "café/n ", SegmentsToString(seg.Segment([]byte("Café")), false))
+	expect(t, "cafÉ/x ", SegmentsToString(seg.Segment([]byte("CAFÉ")), false))
+
+	unicodeSeg := NewSegmenter(WithUnicodeLowercase())
+	unicodeSeg.LoadDictionary("CAFÉ 10 n\n")
+	segments := unicodeSeg.Segment([]byte("CAFÉ和Café"))
+	expect(t, "café/n 和/x café/n ", SegmentsToString(segments, false))
+	expect(t, "5", segments[0].End())
+	expect(t, "8", segments[2].Start())
+	expect(t, "13", segments[2].End())
+
+	// 小写形式编码长度不同的字符保持不变
+	expect(t, "İ", string(toLowerUnicode([]byte("İ"))))
+	expect(t, "àαİ", string(toLowerUnicode([]byte("ÀΑİ"))))
+}
@@ -0,0 +1,30 @@
+package sego
+
+// 对文本分词，分词时按deltas临时调整分词的路径长度，不改变词典
+//
+// deltas的键为分词文本，值加到该分词的路径长度（见Token结构体中distance的注释）
+// 上：负值使该分词更容易被选中，正值使其更难被选中。键和词典中的分词一样经过
+// 规范化和字元划分，英文不区分大小写。调整对词典中的分词和伪分词都有效，但不
+// 影响搜索模式下的子分词。可以用于由调用者的上下文决定的消歧。
+//
+// 该函数不使用句子缓存，可以和其他分词并发调用。
+func (seg *Segmenter) SegmentWithOverrides(bytes []byte, deltas map[string]float32) []Segment {
+	overridden := *seg
+	overridden.cache = nil
+	if len(deltas) > 0 {
+		overridden.overrides = make(map[string]float32, len(deltas))
+		for text, delta := range deltas {
+			key := textSliceToString(seg.splitText(seg.normalizeText([]byte(text))))
+			overridden.overrides[key] += delta
+		}
+	}
+	return overridden.internalSegment(bytes, false)
+}
+
+// 返回本次分词中token的路径长度调整值
+func (seg *Segmenter) overrideDelta(token *Token) float32 {
+	if len(seg.overrides) == 0 {
+		return 0
+	}
+	return seg.overrides[token.Text()]
+}
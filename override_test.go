@@ -0,0 +1,25 @@
+package sego
+
+import (
+	"testing"
+)
+
+func TestSegmentWithOverrides(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("南京 10 ns\n南京市 10 ns\n市长 10 n\n长江 10 ns\n长江大桥 10 nz\n大桥 10 n\n")
+	text := []byte("南京市长江大桥")
+	expect(t, "南京市/ns 长江大桥/nz ", SegmentsToString(seg.Segment(text), false))
+
+	expect(t, "南京/ns 市长/n 江/x 大桥/n ",
+		SegmentsToString(seg.SegmentWithOverrides(text, map[string]float32{"市长": -40}), false))
+	expect(t, "南京市/ns 长江/ns 大桥/n ",
+		SegmentsToString(seg.SegmentWithOverrides(text, map[string]float32{"长江大桥": 10}), false))
+	expect(t, "南京市/ns 长江大桥/nz ", SegmentsToString(seg.SegmentWithOverrides(text, nil), false))
+
+	// 不改变词典
+	expect(t, "南京市/ns 长江大桥/nz ", SegmentsToString(seg.Segment(text), false))
+
+	// 对伪分词也有效
+	expect(t, "南京/ns 市长/n 江/x 大桥/n ",
+		SegmentsToString(seg.SegmentWithOverrides(text, map[string]float32{"江": -100}), false))
+}
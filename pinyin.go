@@ -0,0 +1,153 @@
+package sego
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// 汉字的一个读音
+type pinyinReading struct {
+	// 带声调数字的拼音，比如"zhong1"
+	syllable string
+
+	// 优先使用该读音的词性，为空时只作为默认读音
+	pos []string
+}
+
+// 从文件中载入汉字拼音表
+//
+// 文件的格式为（每个读音一行，#开头的行为注释）：
+//
+//	汉字 拼音 [词性1 词性2 ...]
+//
+// 拼音用数字标注声调，比如"zhong1"。多音字每个读音各占一行，第一行的读音为
+// 默认读音；行尾的词性表示分词为这些词性时优先使用该读音，词性按前缀匹配，
+// 比如"n"匹配"ns"和"nr"。例如：
+//
+//	长 chang2
+//	长 zhang3 v
+//
+// 该函数需要在词典载入之后调用。
+func (dict *Dictionary) LoadPinyinTable(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	table := make(map[rune][]pinyinReading)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(fields[0])
+		if size != len(fields[0]) {
+			continue
+		}
+		table[r] = append(table[r], pinyinReading{syllable: fields[1], pos: fields[2:]})
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	dict.pinyin = table
+	return nil
+}
+
+// 返回汉字r在词性为pos的分词中的读音，拼音表中没有该字时返回false
+func (dict *Dictionary) readingOf(r rune, pos string) (string, bool) {
+	readings := dict.pinyin[r]
+	if len(readings) == 0 {
+		return "", false
+	}
+	for _, reading := range readings {
+		for _, p := range reading.pos {
+			if strings.HasPrefix(pos, p) {
+				return reading.syllable, true
+			}
+		}
+	}
+	return readings[0].syllable, true
+}
+
+// 返回分词的拼音，各音节用空格分隔
+//
+// 拼音表中没有的字（比如英文词和标点）原样输出。
+func (dict *Dictionary) tokenPinyin(token *Token) string {
+	syllables := make([]string, 0, len(token.text))
+	for _, word := range token.text {
+		r, size := utf8.DecodeRune(word)
+		if size == len(word) {
+			if syllable, ok := dict.readingOf(r, token.pos); ok {
+				syllables = append(syllables, syllable)
+				continue
+			}
+		}
+		syllables = append(syllables, string(word))
+	}
+	return strings.Join(syllables, " ")
+}
+
+// 为分词结果标注拼音
+//
+// 拼音表通过Dictionary.LoadPinyinTable载入，多音字按照分词的词性选择读音。
+// 拼音表中没有的字元原样输出。
+func (seg *Segmenter) AnnotatePinyin(segs []Segment) []AnnotatedSegment {
+	output := make([]AnnotatedSegment, len(segs))
+	for i, s := range segs {
+		output[i].Segment = s
+		if seg.dict != nil {
+			output[i].pinyin = seg.dict.tokenPinyin(s.token)
+		} else {
+			output[i].pinyin = textSliceToString(s.token.text)
+		}
+	}
+	return output
+}
+
+// 返回词典中和word读音相同（所有音节包括声调都相同）的其他词，按词典中的顺序排列
+//
+// 需要先用LoadPinyinTable载入拼音表。word在词典中时按其词性选择多音字的读音，
+// 否则使用默认读音。word中有拼音表里没有的字时返回nil。该函数遍历整个词典。
+func (dict *Dictionary) Homophones(word string) []string {
+	if len(dict.pinyin) == 0 {
+		return nil
+	}
+	token := dict.tokenOrNew(word, "")
+	pinyin, ok := dict.fullPinyin(token)
+	if !ok {
+		return nil
+	}
+
+	var homophones []string
+	for i := range dict.tokens {
+		candidate := dict.token(i)
+		if candidate == token || len(candidate.text) != len(token.text) {
+			continue
+		}
+		if p, ok := dict.fullPinyin(candidate); ok && p == pinyin {
+			homophones = append(homophones, candidate.Text())
+		}
+	}
+	return homophones
+}
+
+// 返回分词的拼音，分词中有拼音表里没有的字时返回false
+func (dict *Dictionary) fullPinyin(token *Token) (string, bool) {
+	syllables := make([]string, len(token.text))
+	for i, word := range token.text {
+		r, size := utf8.DecodeRune(word)
+		if size != len(word) {
+			return "", false
+		}
+		syllable, ok := dict.readingOf(r, token.pos)
+		if !ok {
+			return "", false
+		}
+		syllables[i] = syllable
+	}
+	return strings.Join(syllables, " "), true
+}
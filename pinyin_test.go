@@ -0,0 +1,50 @@
+package sego
+
+import (
+	"testing"
+)
+
+func pinyinToString(segs []AnnotatedSegment) (output string) {
+	for _, s := range segs {
+		output += s.Token().Text() + "/" + s.Pinyin() + " "
+	}
+	return
+}
+
+func TestAnnotatePinyin(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n长城 10 ns\n长大 10 v\n银行 10 n\n行 10 v\n")
+
+	// 未载入拼音表时输出原文
+	expect(t, "中国/中 国 ", pinyinToString(seg.AnnotatePinyin(seg.Segment([]byte("中国")))))
+
+	expect(t, "<nil>", seg.dict.LoadPinyinTable("testdata/test_pinyin.txt"))
+	segments := seg.AnnotatePinyin(seg.Segment([]byte("中国长城，长大银行行ok")))
+	expect(t, "中国/zhong1 guo2 长城/chang2 cheng2 ，/， 长大/zhang3 da4 银行/yin2 hang2 行/xing2 ok/ok ",
+		pinyinToString(segments))
+	expect(t, "6", segments[1].start)
+
+	expect(t, "true", seg.dict.LoadPinyinTable("testdata/not_exist.txt") != nil)
+}
+
+func TestHomophones(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("长城 10 ns\n常成 10 n\n长成 10 v\n城 10 n\n成 10 v\n中国ok 10 n\n")
+	dict := seg.Dictionary()
+
+	// 未载入拼音表
+	expect(t, "[]", dict.Homophones("长城"))
+
+	expect(t, "<nil>", dict.LoadPinyinTable("testdata/test_pinyin.txt"))
+	expect(t, "[常成]", dict.Homophones("长城"))
+	expect(t, "[长城]", dict.Homophones("常成"))
+	expect(t, "[]", dict.Homophones("长成"))
+	expect(t, "[成]", dict.Homophones("城"))
+
+	// 不在词典中的词使用默认读音
+	expect(t, "[长城 常成]", dict.Homophones("常城"))
+
+	// 有拼音表里没有的字
+	expect(t, "[]", dict.Homophones("中国ok"))
+	expect(t, "[]", dict.Homophones("天"))
+}
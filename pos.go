@@ -0,0 +1,254 @@
+package sego
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 词性标签
+//
+// 词性是分层的：标签的前缀是它的上层词性，比如"ns"（地名）和"nr"（人名）都
+// 属于"n"（名词），"vn"（名动词）属于"v"（动词）。
+type POS string
+
+// 判断词性是否属于parent或者就是parent，即标签是否以parent开头
+//
+// parent为空时对任何词性都返回true。
+func (pos POS) IsA(parent string) bool {
+	return strings.HasPrefix(string(pos), parent)
+}
+
+// 分词的一个词性及其权重
+type POSWeight struct {
+	POS    string
+	Weight float64
+}
+
+// 返回分词的全部词性及权重，按词典中的顺序排列
+//
+// 词典中只给出一个没有权重的词性时返回权重为1的该词性，没有词性时返回空。
+// 分词仍然只按Pos()返回的权重最大的词性输出，这里的列表供需要全部词性的
+// 下游使用。
+func (token *Token) POSTags() []POSWeight {
+	if token.posTags != nil {
+		return token.posTags
+	}
+	if token.pos == "" {
+		return nil
+	}
+	return []POSWeight{{POS: token.pos, Weight: 1}}
+}
+
+// 解析词典中的词性字段，比如"n"或者"vn:0.7,n:0.3"
+//
+// 返回权重最大的词性（权重相同时取靠前的）和全部词性，没有写权重的词性权重
+// 为1。字段中没有逗号和冒号时tags为nil。词性为空或者权重不是非负数时ok为false。
+func parsePOSField(field string) (pos string, tags []POSWeight, ok bool) {
+	if !strings.ContainsAny(field, ",:") {
+		return field, nil, true
+	}
+	best := -1.0
+	for _, item := range strings.Split(field, ",") {
+		tag := POSWeight{POS: item, Weight: 1}
+		if i := strings.IndexByte(item, ':'); i >= 0 {
+			weight, err := strconv.ParseFloat(item[i+1:], 64)
+			if err != nil || !(weight >= 0) {
+				return "", nil, false
+			}
+			tag = POSWeight{POS: item[:i], Weight: weight}
+		}
+		if tag.POS == "" {
+			return "", nil, false
+		}
+		if tag.Weight > best {
+			pos, best = tag.POS, tag.Weight
+		}
+		tags = append(tags, tag)
+	}
+	return pos, tags, true
+}
+
+// 将分词的词性还原为词典中词性字段的格式，parsePOSField的逆操作
+func formatPOSField(token *Token) string {
+	if token.posTags == nil {
+		return token.pos
+	}
+	items := make([]string, len(token.posTags))
+	for i, tag := range token.posTags {
+		items[i] = tag.POS + ":" + strconv.FormatFloat(tag.Weight, 'g', -1, 64)
+	}
+	return strings.Join(items, ",")
+}
+
+// 返回词典中词性属于pos（见POS.IsA）的所有分词，按在词典中的顺序排列
+func (dict *Dictionary) TokensByPOS(pos string) []*Token {
+	output := []*Token{}
+	for i := range dict.tokens {
+		if token := dict.token(i); POS(token.pos).IsA(pos) {
+			output = append(output, token)
+		}
+	}
+	return output
+}
+
+// 设置词典中没有的字元对应的伪分词的词性，默认为"x"
+//
+// 只影响不属于数字（"m"）、标点（"w"）和空白（"s"）的伪分词，见pseudoPOS。
+// 非法UTF8字节的词性仍然是"err"，保护模式匹配的词性不受影响。已经载入的词典
+// 中分词的子分词在下一次分词时按新的词性重建。该函数不能和分词并发调用。
+func (seg *Segmenter) SetUnknownPOS(pos string) {
+	seg.unknownPOS = pos
+	if seg.dict != nil {
+		seg.dict.unknownPOS = pos
+		seg.dict.markDirty()
+	}
+	seg.cache.clear()
+}
+
+// 设置词典允许使用的词性
+//
+// 设置后LoadDictionary跳过词性不在tags中的行并输出错误日志，AddToken和
+// ForceWord忽略这样的分词，以免下游按固定词性表处理分词结果时遇到意料之外
+// 的值。没有词性的行不受影响。伪分词的词性（见SetUnknownPOS）不做检查，
+// 需要时请把它也列在tags中。
+func WithPOSVocabulary(tags ...string) Option {
+	return func(seg *Segmenter) {
+		seg.posVocabulary = make(map[string]bool, len(tags))
+		for _, tag := range tags {
+			seg.posVocabulary[tag] = true
+		}
+	}
+}
+
+// 判断词性是否可以使用，见WithPOSVocabulary
+func (seg *Segmenter) knownPOS(pos string) bool {
+	return seg.posVocabulary == nil || pos == "" || seg.posVocabulary[pos]
+}
+
+// 判断词典条目的所有词性是否都可以使用
+func (seg *Segmenter) knownPOSTags(entry dictEntry) bool {
+	if entry.posTags == nil {
+		return seg.knownPOS(entry.pos)
+	}
+	for _, tag := range entry.posTags {
+		if !seg.knownPOS(tag.POS) {
+			return false
+		}
+	}
+	return true
+}
+
+// 返回字元word对应的类别为kind的伪分词的词性
+//
+// 按字元中字符的Unicode类别区分：非法UTF8字节为"err"，数字为"m"，标点为"w"，
+// 空白为"s"，其他为unknown或者"x"。
+func pseudoPOS(word Text, kind Kind, unknown string) string {
+	switch {
+	case kind == KindInvalid:
+		return "err"
+	case allRunes(word, unicode.IsNumber):
+		return "m"
+	case allRunes(word, unicode.IsPunct):
+		return "w"
+	case allRunes(word, unicode.IsSpace):
+		return "s"
+	case unknown == "":
+		return "x"
+	}
+	return unknown
+}
+
+// 判断word中的每个字符是否都满足is，word为空时返回false
+func allRunes(word Text, is func(rune) bool) bool {
+	if len(word) == 0 {
+		return false
+	}
+	for current := 0; current < len(word); {
+		r, size := utf8.DecodeRune(word[current:])
+		if !is(r) {
+			return false
+		}
+		current += size
+	}
+	return true
+}
+
+// 对文本分词，返回的分词可以直接取得词性、词频和路径长度
+//
+// 分词结果和Segment相同。
+func (seg *Segmenter) SegmentPOS(text []byte) []TaggedSegment {
+	segments := seg.internalSegment(text, false)
+	output := make([]TaggedSegment, len(segments))
+	for i, s := range segments {
+		output[i].Segment = s
+	}
+	return output
+}
+
+// 对文本分词，只返回词性在allowed中的分词
+//
+// 伪分词（词性为"x"、"m"、"w"或"s"）和非法字节（词性"err"）只有在allowed中
+// 显式列出时才会保留。保留的分词位置和Segment的结果一致。
+func (seg *Segmenter) SegmentByPOS(bytes []byte, allowed map[string]bool) []Segment {
+	output := []Segment{}
+	for _, s := range seg.internalSegment(bytes, false) {
+		if allowed[s.token.pos] {
+			output = append(output, s)
+		}
+	}
+	return output
+}
+
+// 和SegmentByPOS相同，但被丢弃的分词合并到相邻的保留分词中
+//
+// 被丢弃的分词合并到前一个保留分词中，文本开头的被丢弃分词合并到第一个保留
+// 分词中。合并后分词的词性和词频取自保留分词，位置覆盖所有被合并的分词。
+// 没有任何分词被保留时返回空。
+func (seg *Segmenter) SegmentByPOSMerged(bytes []byte, allowed map[string]bool) []Segment {
+	segments := seg.internalSegment(bytes, false)
+	output := []Segment{}
+
+	// 每个保留分词和合并到它的分词组成一组
+	group := []Segment{}
+	kept := -1
+	for _, s := range segments {
+		if allowed[s.token.pos] {
+			if kept >= 0 {
+				output = append(output, mergeSegments(group, group[kept].token))
+				group = group[:0]
+			}
+			kept = len(group)
+		}
+		group = append(group, s)
+	}
+	if kept >= 0 {
+		output = append(output, mergeSegments(group, group[kept].token))
+	}
+	return output
+}
+
+// 将多个相邻的分词合并为一个分词
+//
+// 合并后的分词使用新的Token，其文本为各分词文本的拼接，词频、路径长度和词性
+// 取自base，原分词复制一份保存在components中。只有一个分词时直接返回该分词。
+func mergeSegments(segs []Segment, base *Token) Segment {
+	if len(segs) == 1 {
+		return segs[0]
+	}
+
+	token := &Token{frequency: base.frequency, distance: base.distance, pos: base.pos, kind: base.kind}
+	for _, s := range segs {
+		token.text = append(token.text, s.token.text...)
+	}
+	last := segs[len(segs)-1]
+	return Segment{
+		start:      segs[0].start,
+		end:        last.end,
+		runeStart:  segs[0].runeStart,
+		runeEnd:    last.runeEnd,
+		token:      token,
+		components: append([]Segment(nil), segs...),
+	}
+}
@@ -0,0 +1,142 @@
+package sego
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"testing"
+)
+
+func TestSegmentByPOS(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n的 10 uj\n众多 10 a\n")
+
+	text := []byte("中国的人口众多！")
+	segments := seg.SegmentByPOS(text, map[string]bool{"n": true, "ns": true})
+	expect(t, "中国/ns 人口/n ", SegmentsToString(segments, false))
+	expect(t, "9", segments[1].start)
+	expect(t, "15", segments[1].end)
+
+	// 伪分词需要显式允许
+	segments = seg.SegmentByPOS(text, map[string]bool{"w": true})
+	expect(t, "！/w ", SegmentsToString(segments, false))
+
+	segments = seg.SegmentByPOS(text, map[string]bool{})
+	expect(t, "0", len(segments))
+}
+
+func TestSegmentByPOSMerged(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n的 10 uj\n众多 10 a\n")
+
+	text := []byte("的中国的人口众多！")
+	segments := seg.SegmentByPOSMerged(text, map[string]bool{"n": true, "ns": true})
+	expect(t, "的中国的/ns 人口众多！/n ", SegmentsToString(segments, false))
+	expect(t, "0", segments[0].start)
+	expect(t, "12", segments[0].end)
+	expect(t, "12", segments[1].start)
+	expect(t, "27", segments[1].end)
+	expect(t, "4", segments[0].runeEnd)
+	expect(t, "9", segments[1].runeEnd)
+
+	segments = seg.SegmentByPOSMerged(text, map[string]bool{"v": true})
+	expect(t, "0", len(segments))
+}
+
+func TestPOSIsA(t *testing.T) {
+	expect(t, "true", POS("ns").IsA("n"))
+	expect(t, "true", POS("nr").IsA("n"))
+	expect(t, "true", POS("n").IsA("n"))
+	expect(t, "false", POS("n").IsA("ns"))
+	expect(t, "false", POS("vn").IsA("n"))
+	expect(t, "true", POS("vn").IsA("v"))
+	expect(t, "true", POS("uj").IsA(""))
+}
+
+func TestTokensByPOS(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n的 10 uj\n张三 10 nr\n众多 10 a\n")
+	dict := seg.Dictionary()
+
+	expect(t, "中国/ns 人口/n 张三/nr ", tokensToString(dict.TokensByPOS("n")))
+	expect(t, "中国/ns ", tokensToString(dict.TokensByPOS("ns")))
+	expect(t, "", tokensToString(dict.TokensByPOS("v")))
+	expect(t, "5", len(dict.TokensByPOS("")))
+}
+
+func TestSetUnknownPOS(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n中国人民 10 nt\n")
+	expect(t, "中国/ns 有/x ", SegmentsToString(seg.Segment([]byte("中国有")), false))
+
+	seg.SetUnknownPOS("unk")
+	expect(t, "中国/ns 有/unk \xff/err ", SegmentsToString(seg.Segment([]byte("中国有\xff")), false))
+	// 子分词也使用新的词性
+	expect(t, "中国/ns 人/unk 民/unk 中国人民/nt ", SegmentsToString(seg.Segment([]byte("中国人民")), true))
+}
+
+func TestPOSVocabulary(t *testing.T) {
+	var buf bytes.Buffer
+	seg := NewSegmenter(WithPOSVocabulary("n", "ns", "x"))
+	seg.SetLogger(NewStdLogger(log.New(&buf, "", 0)))
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n众多 10 adj\n的 10\n")
+
+	expect(t, "3", seg.Dictionary().NumTokens())
+	expect(t, "[ERROR] sego词典中的词性不在词性表中 line=3 text=众多 pos=adj\n"+
+		"[INFO] sego词典字符串载入完毕 tokens_loaded=3 lines_skipped=1\n", buf.String())
+
+	seg.AddToken("众多", 10, "adj")
+	seg.AddToken("很多", 10, "n")
+	expect(t, "4", seg.Dictionary().NumTokens())
+}
+
+func TestPOSTags(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("研究 10 n:0.3,vn:0.7\n中国 10 ns\n生命 10 n,v\n起源 10\n错误 10 n:abc\n")
+	dict := seg.Dictionary()
+	expect(t, "4", dict.NumTokens())
+
+	tags := func(text string) string {
+		token := dict.tokenOrNew(text, "")
+		return fmt.Sprintf("%s %v", token.Pos(), token.POSTags())
+	}
+	expect(t, "vn [{n 0.3} {vn 0.7}]", tags("研究"))
+	expect(t, "ns [{ns 1}]", tags("中国"))
+	expect(t, "n [{n 1} {v 1}]", tags("生命"))
+	expect(t, " []", tags("起源"))
+
+	// 分词输出权重最大的词性
+	expect(t, "研究/vn 生命/n 起源/ ", SegmentsToString(seg.Segment([]byte("研究生命起源")), false))
+
+	// 多词性的每个词性都要在词性表中
+	vocabulary := NewSegmenter(WithPOSVocabulary("n", "ns"))
+	vocabulary.LoadDictionary("研究 10 n,vn\n中国 10 n,ns\n")
+	expect(t, "1", vocabulary.Dictionary().NumTokens())
+}
+
+func TestSegmentPOS(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 30 n\n")
+
+	output := ""
+	for _, s := range seg.SegmentPOS([]byte("中国人口！")) {
+		output += fmt.Sprintf("%s/%s/%d/%.0f[%d:%d] ", s.Token().Text(), s.POS(), s.Frequency(), s.Distance(), s.Start(), s.End())
+	}
+	expect(t, "中国/ns/10/2[0:6] 人口/n/30/0[6:12] ！/w/1/32[12:15] ", output)
+	expect(t, "0", len(seg.SegmentPOS(nil)))
+}
+
+func TestPseudoPOS(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n")
+	expect(t, "中国/ns ，/w 2024/m 年/x  /s 【/w ①/m ★/x abc/x \xff/err ",
+		SegmentsToString(seg.Segment([]byte("中国，2024年 【①★abc\xff")), false))
+
+	// SetUnknownPOS只影响其他类别的伪分词
+	seg.SetUnknownPOS("un")
+	expect(t, "中国/ns ，/w 2024/m 年/un ", SegmentsToString(seg.Segment([]byte("中国，2024年")), false))
+
+	// 没有词典时也一样
+	var empty Segmenter
+	expect(t, "1/m ，/w \t/s 甲/x ", SegmentsToString(empty.Segment([]byte("1，\t甲")), false))
+}
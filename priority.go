@@ -0,0 +1,68 @@
+package sego
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// 从多个来源载入词典，后面的来源中的分词覆盖前面来源中的同一分词
+//
+// 每个来源的格式和LoadDictionary相同。同一个分词（英文不区分大小写）出现在
+// 多个来源中时，使用最后一个来源中的定义，词频和词性都整个替换，而不是把词频
+// 相加；同一来源中重复的分词和LoadDictionary一样以第一次出现的为准。分词在
+// 词典中的顺序是它第一次出现的顺序。
+//
+// 读取任何一个来源出错时返回该错误，分词器原来的词典保持不变。
+func (seg *Segmenter) LoadDictionariesPriority(readers ...io.Reader) error {
+	type definition struct {
+		index  int // 在tokens中的下标
+		source int // 来源的序号
+	}
+	tokens := []Token{}
+	definitions := make(map[string]definition)
+	linesSkipped := 0
+	for source, r := range readers {
+		reader := bufio.NewReader(r)
+		for lineNumber := 1; ; lineNumber++ {
+			line, err := reader.ReadString('\n')
+			if err != nil && err != io.EOF {
+				return err
+			}
+			if err != nil && len(line) == 0 {
+				break
+			}
+			if lineNumber == 1 {
+				line = strings.TrimPrefix(line, utf8BOM)
+			}
+
+			token, ok, blank := seg.dictionaryLineToken(line, lineNumber)
+			if !ok {
+				if !blank {
+					linesSkipped++
+				}
+				continue
+			}
+			key := string(textSliceToBytes(token.text))
+			if first, ok := definitions[key]; !ok {
+				definitions[key] = definition{len(tokens), source}
+				tokens = append(tokens, token)
+			} else if first.source < source {
+				tokens[first.index] = token
+				definitions[key] = definition{first.index, source}
+			}
+		}
+	}
+
+	dict := seg.newDictionary()
+	for _, token := range tokens {
+		dict.addToken(token)
+	}
+	dict.Rebuild()
+	seg.dict = dict
+	seg.cache.clear()
+
+	seg.logInfo("sego词典载入完毕", "sources", len(readers),
+		"tokens_loaded", dict.NumTokens(), "lines_skipped", linesSkipped)
+	return nil
+}
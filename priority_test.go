@@ -0,0 +1,46 @@
+package sego
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLoadDictionariesPriority(t *testing.T) {
+	base := "\uFEFF中国 10 ns\n人口 10 n\n研究 5 vn\n研究 50 n\n"
+	domain := "研究 20 v\n研究 30 n\n众多 8 a\nGitHub 3 nz\n"
+	custom := "# 最高优先级\n中国 100 nz\ngithub 7 n\n研究 1 x\n"
+
+	var seg Segmenter
+	err := seg.LoadDictionariesPriority(strings.NewReader(base), strings.NewReader(domain), strings.NewReader(custom))
+	expect(t, "<nil>", err)
+	dict := seg.Dictionary()
+	expect(t, "5", dict.NumTokens())
+	// 后面的来源整个替换词频和词性，同一来源中以第一次出现的为准，低频的行被忽略
+	expect(t, "中国/nz/100 人口/n/10 研究/v/20 众多/a/8 github/n/7 ", priorityTokens(dict))
+	expect(t, "145", dict.TotalFrequency())
+
+	// 读取出错时词典不变
+	err = seg.LoadDictionariesPriority(strings.NewReader("新词 10 n\n"), failingReader{})
+	expect(t, "读取失败", err)
+	expect(t, "5", seg.Dictionary().NumTokens())
+
+	var empty Segmenter
+	expect(t, "<nil>", empty.LoadDictionariesPriority())
+	expect(t, "false", empty.IsReady())
+}
+
+func priorityTokens(dict *Dictionary) (output string) {
+	for i := range dict.tokens {
+		token := &dict.tokens[i]
+		output += token.Text() + "/" + token.Pos() + "/" + strconv.Itoa(token.Frequency()) + " "
+	}
+	return
+}
+
+type failingReader struct{}
+
+func (failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("读取失败")
+}
@@ -0,0 +1,54 @@
+package sego
+
+// 载入进度回调的调用间隔：每解析这么多行或者每处理这么多分词调用一次
+const progressInterval = 10000
+
+// 词典载入所处的阶段
+type LoadStage int
+
+const (
+	// 逐行解析词典文本
+	LoadStageParse LoadStage = iota
+
+	// 计算路径值并构建子分词，通常是载入中最慢的部分
+	LoadStageRebuild
+
+	// 载入完毕，LoadProgress.Tokens为词典的最终分词数
+	LoadStageDone
+)
+
+// 返回阶段的名称
+func (stage LoadStage) String() string {
+	switch stage {
+	case LoadStageParse:
+		return "parse"
+	case LoadStageRebuild:
+		return "rebuild"
+	case LoadStageDone:
+		return "done"
+	}
+	return "unknown"
+}
+
+// 词典载入进度
+type LoadProgress struct {
+	// 当前阶段
+	Stage LoadStage
+
+	// 已经解析的行数
+	Lines int
+
+	// LoadStageRebuild阶段为已经处理的分词数，LoadStageDone阶段为词典的分词总数
+	Tokens int
+}
+
+// 和LoadDictionary相同，但在载入过程中调用progress报告进度，方便命令行
+// 工具显示进度条
+//
+// 解析阶段每progressInterval行调用一次，解析结束时再调用一次；重建阶段每
+// 处理progressInterval个分词调用一次，结束时再调用一次；最后以LoadStageDone
+// 调用一次并报告最终的分词数。progress为nil时等同于LoadDictionary。回调在
+// 调用LoadDictionaryWithProgress的goroutine中同步执行。
+func (seg *Segmenter) LoadDictionaryWithProgress(content string, progress func(LoadProgress)) {
+	seg.loadDictionary(content, progress)
+}
@@ -0,0 +1,35 @@
+package sego
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLoadDictionaryWithProgress(t *testing.T) {
+	var builder strings.Builder
+	numLines := progressInterval + 5
+	for i := 0; i < numLines; i++ {
+		fmt.Fprintf(&builder, "词%d 10 n\n", i)
+	}
+
+	var seg Segmenter
+	var reports []LoadProgress
+	seg.LoadDictionaryWithProgress(builder.String(), func(p LoadProgress) {
+		reports = append(reports, p)
+	})
+
+	var stages []string
+	for _, p := range reports {
+		stages = append(stages, fmt.Sprintf("%s:%d:%d", p.Stage, p.Lines, p.Tokens))
+	}
+	expect(t, fmt.Sprintf("[parse:10000:0 parse:%d:0 rebuild:%d:10000 rebuild:%d:%d done:%d:%d]",
+		numLines, numLines, numLines, numLines, numLines, numLines),
+		fmt.Sprint(stages))
+	expect(t, fmt.Sprint(numLines), seg.Dictionary().NumTokens())
+
+	// progress为nil时和LoadDictionary相同
+	var plain Segmenter
+	plain.LoadDictionaryWithProgress("中国 10 ns\n", nil)
+	expect(t, "1", plain.Dictionary().NumTokens())
+}
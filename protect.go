@@ -0,0 +1,129 @@
+package sego
+
+import (
+	"regexp"
+	"sort"
+	"unicode/utf8"
+)
+
+// 一个保护模式
+type protectPattern struct {
+	re   *regexp.Regexp
+	pos  string
+	kind Kind
+}
+
+// 保护区间在文本中的字节位置
+type protectedSpan struct {
+	start, end int
+	pos        string
+	kind       Kind
+}
+
+var (
+	// 网址，只包含RFC 3986允许的ASCII字符，不以标点结尾
+	urlPattern = regexp.MustCompile(`(?i)(?:(?:https?|ftp)://|www\.)` +
+		`[a-z0-9\-._~:/?#\[\]@!$&'()*+,;=%]*[a-z0-9\-_~/#=&+@%]`)
+
+	// 电子邮件地址
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`)
+)
+
+// 启用网址保护，文本中的网址（比如"http://www.example.com/a?b=c"）作为一个
+// 词性为"url"的分词输出，见AddProtectPattern
+func (seg *Segmenter) EnableURLProtection() {
+	seg.addProtectPattern(urlPattern, "url", KindURL)
+}
+
+// 启用电子邮件地址保护，文本中的电子邮件地址作为一个词性为"email"的分词输出，
+// 见AddProtectPattern
+func (seg *Segmenter) EnableEmailProtection() {
+	seg.addProtectPattern(emailPattern, "email", KindEmail)
+}
+
+// 加入一个保护模式，文本中匹配re的部分不会被切分
+//
+// 分词前先找出所有保护模式的匹配，每个匹配作为一个不可分割的分词（词性为"x"，
+// 文本保持原样不转小写）直接输出，不参与最短路径计算；匹配之间的文本照常分词。
+// 这可以用来保护网址、电话号码、商品型号等。
+//
+// 保护模式按加入的顺序处理，和先加入的模式的匹配重叠的匹配会被忽略。该函数
+// 不能和分词并发调用。
+func (seg *Segmenter) AddProtectPattern(re *regexp.Regexp) {
+	seg.addProtectPattern(re, "x", KindProtected)
+}
+
+// 加入一个匹配词性为pos、类别为kind的保护模式，同一个模式重复加入时只保留第一次
+func (seg *Segmenter) addProtectPattern(re *regexp.Regexp, pos string, kind Kind) {
+	for _, pattern := range seg.protectPatterns {
+		if pattern.re == re {
+			return
+		}
+	}
+	seg.protectPatterns = append(seg.protectPatterns, protectPattern{re: re, pos: pos, kind: kind})
+}
+
+// 找出文本中所有的保护区间，按起始位置排序
+func (seg *Segmenter) findProtectedSpans(bytes []byte) []protectedSpan {
+	spans := []protectedSpan{}
+	for _, pattern := range seg.protectPatterns {
+		for _, match := range pattern.re.FindAllIndex(bytes, -1) {
+			if match[0] == match[1] {
+				continue
+			}
+			overlapped := false
+			for _, span := range spans {
+				if match[0] < span.end && span.start < match[1] {
+					overlapped = true
+					break
+				}
+			}
+			if !overlapped {
+				spans = append(spans, protectedSpan{start: match[0], end: match[1],
+					pos: pattern.pos, kind: pattern.kind})
+			}
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	return spans
+}
+
+// 对有保护区间的文本分词，out不为nil时结果写入out[:0]
+func (seg *Segmenter) segmentProtected(bytes []byte, searchMode bool, out []Segment) []Segment {
+	output := out[:0]
+	bytePosition := 0
+	runePosition := 0
+
+	// 对保护区间之前的文本照常分词，并将分词位置平移到文本中的实际位置
+	segmentBefore := func(end int) {
+		if bytePosition == end {
+			return
+		}
+		for _, s := range seg.segmentWords(seg.splitText(bytes[bytePosition:end]), searchMode) {
+			s.start += bytePosition
+			s.end += bytePosition
+			s.runeStart += runePosition
+			s.runeEnd += runePosition
+			output = append(output, s)
+		}
+		runePosition += utf8.RuneCount(bytes[bytePosition:end])
+		bytePosition = end
+	}
+
+	for _, span := range seg.findProtectedSpans(bytes) {
+		segmentBefore(span.start)
+		runeLength := utf8.RuneCount(bytes[span.start:span.end])
+		output = append(output, Segment{
+			start:     span.start,
+			end:       span.end,
+			runeStart: runePosition,
+			runeEnd:   runePosition + runeLength,
+			token: &Token{text: []Text{bytes[span.start:span.end]},
+				frequency: 1, pos: span.pos, kind: span.kind},
+		})
+		bytePosition = span.end
+		runePosition += runeLength
+	}
+	segmentBefore(len(bytes))
+	return output
+}
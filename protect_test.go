@@ -0,0 +1,55 @@
+package sego
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestAddProtectPattern(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("电话 10 n\n型号 10 n\n")
+	text := []byte("电话13800138000型号YZL-1806052")
+	expect(t, "电话/n 13800138000/m 型号/n yzl/x -/w 1806052/m ",
+		SegmentsToString(seg.Segment(text), false))
+
+	seg.AddProtectPattern(regexp.MustCompile(`[A-Z]+-[0-9]+`))
+	seg.AddProtectPattern(regexp.MustCompile(`[0-9]{11}`))
+	// 和第一个模式的匹配重叠，被忽略
+	seg.AddProtectPattern(regexp.MustCompile(`L-1`))
+	segments := seg.Segment(text)
+	expect(t, "电话/n 13800138000/x 型号/n YZL-1806052/x ", SegmentsToString(segments, false))
+	expect(t, "6", segments[1].start)
+	expect(t, "17", segments[1].end)
+	expect(t, "2", segments[1].runeStart)
+	expect(t, "13", segments[1].runeEnd)
+	expect(t, "23", segments[3].start)
+	expect(t, "34", segments[3].end)
+	expect(t, "15", segments[3].runeStart)
+	expect(t, "26", segments[3].runeEnd)
+
+	// 保护区间位于文本首尾
+	segments = seg.Segment([]byte("13800138000"))
+	expect(t, "13800138000/x ", SegmentsToString(segments, false))
+}
+
+func TestURLAndEmailProtection(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("访问 10 v\n联系 10 v\n了解 10 v\n")
+	seg.EnableURLProtection()
+	seg.EnableEmailProtection()
+	seg.EnableURLProtection()
+	expect(t, "2", len(seg.protectPatterns))
+
+	segments := seg.Segment([]byte("访问http://www.example.com/a?b=c&d=1了解，联系Foo.Bar@mail.example.cn。"))
+	expect(t, "访问/v http://www.example.com/a?b=c&d=1/url 了解/v ，/w 联系/v Foo.Bar@mail.example.cn/email 。/w ",
+		SegmentsToString(segments, false))
+	expect(t, "6", segments[1].start)
+	expect(t, "38", segments[1].end)
+
+	// 网址末尾的标点不属于网址
+	segments = seg.Segment([]byte("见www.example.com."))
+	expect(t, "见/x www.example.com/url ./w ", SegmentsToString(segments, false))
+
+	segments = seg.Segment([]byte("(https://example.com/path)"))
+	expect(t, "(/w https://example.com/path/url )/w ", SegmentsToString(segments, false))
+}
@@ -0,0 +1,38 @@
+package sego
+
+// 设置是否将连续相同的伪分词合并为一个分词
+//
+// 比如"哈哈哈哈"中的字不在词典中时默认输出四个伪分词"哈"，设为true时输出一个
+// 分词"哈哈哈哈"，其RepeatCount为4，位置覆盖整个重复串。只合并词典中没有的
+// 单个字元（类别为KindPseudo或KindNumber），非法UTF8字节和词典中的分词不合并。
+func WithCollapseRepeats(collapse bool) Option {
+	return func(seg *Segmenter) {
+		seg.collapseRepeats = collapse
+	}
+}
+
+// 将连续相同的伪分词合并为一个分词，结果直接写回segs
+func collapseRepeats(segs []Segment) []Segment {
+	output := segs[:0]
+	for start := 0; start < len(segs); {
+		end := start + 1
+		if isRepeatable(segs[start].token) {
+			for end < len(segs) && isRepeatable(segs[end].token) &&
+				string(segs[end].token.text[0]) == string(segs[start].token.text[0]) {
+				end++
+			}
+		}
+		merged := mergeSegments(segs[start:end], segs[start].token)
+		if end-start > 1 {
+			merged.repeat = end - start
+		}
+		output = append(output, merged)
+		start = end
+	}
+	return output
+}
+
+// 判断分词是否为可以合并的单个字元的伪分词
+func isRepeatable(token *Token) bool {
+	return (token.kind == KindPseudo || token.kind == KindNumber) && len(token.text) == 1
+}
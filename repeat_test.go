@@ -0,0 +1,28 @@
+package sego
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCollapseRepeats(t *testing.T) {
+	dictionary := "好 10 a\n笑 10 v\n"
+	text := []byte("哈哈哈哈好好笑嘿嘿\xff\xff")
+
+	var plain Segmenter
+	plain.LoadDictionary(dictionary)
+	expect(t, "哈/x 哈/x 哈/x 哈/x 好/a 好/a 笑/v 嘿/x 嘿/x \xff/err \xff/err ",
+		SegmentsToString(plain.Segment(text), false))
+
+	seg := NewSegmenter(WithCollapseRepeats(true))
+	seg.LoadDictionary(dictionary)
+	output := ""
+	for _, s := range seg.Segment(text) {
+		output += fmt.Sprintf("%s/%s*%d[%d:%d|%d:%d] ", s.Token().Text(), s.Token().Pos(),
+			s.RepeatCount(), s.Start(), s.End(), s.RuneStart(), s.RuneEnd())
+	}
+	expect(t, "哈哈哈哈/x*4[0:12|0:4] 好/a*1[12:15|4:5] 好/a*1[15:18|5:6] 笑/v*1[18:21|6:7] "+
+		"嘿嘿/x*2[21:27|7:9] \xff/err*1[27:28|9:10] \xff/err*1[28:29|10:11] ", output)
+	expect(t, "1", len(seg.Segment([]byte("哈"))))
+	expect(t, "0", len(seg.Segment(nil)))
+}
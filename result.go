@@ -0,0 +1,96 @@
+package sego
+
+import (
+	"io"
+	"strings"
+)
+
+// SegmentResult逐行输出分词时的格式
+type OutputFormat int
+
+const (
+	// 每行只有分词文本
+	FormatText OutputFormat = iota
+
+	// 每行为"分词文本/词性"，和SegmentsToString的普通模式相同
+	FormatPOS
+)
+
+// 一次分词的结果，包括分词和被分词的文本
+//
+// SegmentResult实现了io.WriterTo，可以不构建中间字符串直接把分词结果写到网络
+// 连接等io.Writer中。
+type SegmentResult struct {
+	source   []byte
+	segments []Segment
+	format   OutputFormat
+}
+
+// 创建一个分词结果，source为被分词的文本，segs为对它分词的结果
+func NewSegmentResult(source []byte, segs []Segment) *SegmentResult {
+	return &SegmentResult{source: source, segments: segs}
+}
+
+// 对文本分词并返回分词结果
+func (seg *Segmenter) SegmentToResult(bytes []byte) *SegmentResult {
+	return NewSegmentResult(bytes, seg.internalSegment(bytes, false))
+}
+
+// 设置WriteTo的输出格式，默认为FormatText，返回result本身以便链式调用
+func (result *SegmentResult) Format(format OutputFormat) *SegmentResult {
+	result.format = format
+	return result
+}
+
+// 返回被分词的文本
+func (result *SegmentResult) Source() []byte {
+	return result.source
+}
+
+// 返回分词
+func (result *SegmentResult) Segments() []Segment {
+	return result.segments
+}
+
+// 结果每积累这么多字节写一次，减少对底层io.Writer的调用次数
+const resultWriteChunk = 4096
+
+// 将分词结果写入w，每个分词一行，实现io.WriterTo
+//
+// 每行为分词文本（英文为小写）加换行符，格式为FormatPOS时在文本之后加上
+// "/词性"。只包含空白字符的分词不输出。返回写入的字节数和遇到的第一个错误。
+func (result *SegmentResult) WriteTo(w io.Writer) (int64, error) {
+	var written int64
+	buffer := make([]byte, 0, resultWriteChunk)
+	flush := func() error {
+		n, err := w.Write(buffer)
+		written += int64(n)
+		buffer = buffer[:0]
+		return err
+	}
+
+	for _, s := range result.segments {
+		if strings.TrimSpace(textSliceToString(s.token.text)) == "" {
+			continue
+		}
+		for _, word := range s.token.text {
+			buffer = append(buffer, word...)
+		}
+		if result.format == FormatPOS {
+			buffer = append(buffer, '/')
+			buffer = append(buffer, s.token.pos...)
+		}
+		buffer = append(buffer, '\n')
+		if len(buffer) >= resultWriteChunk {
+			if err := flush(); err != nil {
+				return written, err
+			}
+		}
+	}
+	if len(buffer) > 0 {
+		if err := flush(); err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
@@ -0,0 +1,43 @@
+package sego
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// 编译时检查SegmentResult实现了io.WriterTo
+var _ io.WriterTo = (*SegmentResult)(nil)
+
+func TestSegmentResultWriteTo(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n")
+
+	result := seg.SegmentToResult([]byte("中国 GitHub人口！"))
+	expect(t, "中国 GitHub人口！", string(result.Source()))
+	expect(t, "5", len(result.Segments()))
+
+	var buf bytes.Buffer
+	n, err := result.WriteTo(&buf)
+	expect(t, "<nil>", err)
+	expect(t, "中国\ngithub\n人口\n！\n", buf.String())
+	expect(t, fmt.Sprint(buf.Len()), n)
+
+	buf.Reset()
+	result.Format(FormatPOS).WriteTo(&buf)
+	expect(t, "中国/ns\ngithub/x\n人口/n\n！/w\n", buf.String())
+
+	// 较长的结果分多次写入
+	text := strings.Repeat("中国人口", 2000)
+	buf.Reset()
+	n, err = NewSegmentResult(nil, seg.Segment([]byte(text))).WriteTo(&buf)
+	expect(t, "<nil>", err)
+	expect(t, strings.Repeat("中国\n人口\n", 2000), buf.String())
+	expect(t, fmt.Sprint(len(text)+4000), n)
+
+	n, err = result.WriteTo(failingWriter{})
+	expect(t, "0", n)
+	expect(t, "true", err != nil)
+}
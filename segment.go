@@ -8,8 +8,26 @@ type Segment struct {
 	// 分词在文本中的结束字节位置（不包括该位置）
 	end int
 
+	// 分词在文本中的起始字符（Unicode码点）位置
+	runeStart int
+
+	// 分词在文本中的结束字符位置（不包括该位置）
+	runeEnd int
+
 	// 分词信息
 	token *Token
+
+	// 分词是否是词典中的分词，伪分词、受保护的片段和合并得到的分词为false
+	inDictionary bool
+
+	// 合并的连续相同伪分词的个数，零表示没有合并，见WithCollapseRepeats
+	repeat int
+
+	// 合并得到的分词对应的原分词，没有合并时为nil，见Components
+	components []Segment
+
+	// 分词之前被过滤掉的分词个数，见PositionIncrement
+	positionGap int
 }
 
 // 返回分词在文本中的起始字节位置
@@ -22,7 +40,115 @@ func (s *Segment) End() int {
 	return s.end
 }
 
+// 返回分词在文本中的起始字符（Unicode码点）位置
+func (s *Segment) RuneStart() int {
+	return s.runeStart
+}
+
+// 返回分词在文本中的结束字符位置（不包括该位置）
+func (s *Segment) RuneEnd() int {
+	return s.runeEnd
+}
+
 // 返回分词信息
 func (s *Segment) Token() *Token {
 	return s.token
 }
+
+// 返回分词是否对应词典中的一个完整分词，而不是分词时补加的伪分词等
+func (s *Segment) InDictionary() bool {
+	return s.inDictionary
+}
+
+// 返回分词在原文本src中对应的字节，即src[Start():End()]
+//
+// 返回的是原文本中的字节，保留了原来的大小写。src和分词的位置不一致（比如
+// 不是被分词的文本）导致越界时返回空。
+func (s *Segment) Bytes(src []byte) []byte {
+	if s.start < 0 || s.start > s.end || s.end > len(src) {
+		return nil
+	}
+	return src[s.start:s.end]
+}
+
+// 返回分词在原文本src中对应的字符串，见Bytes
+func (s *Segment) String(src []byte) string {
+	return string(s.Bytes(src))
+}
+
+// 返回分词由几个连续相同的伪分词合并而成，没有合并时为1，见WithCollapseRepeats
+func (s *Segment) RepeatCount() int {
+	if s.repeat == 0 {
+		return 1
+	}
+	return s.repeat
+}
+
+// 返回合并得到的分词（比如SegmentQuantities合并的数量词）对应的原分词，
+// 没有经过合并的分词返回nil
+func (s *Segment) Components() []Segment {
+	return s.components
+}
+
+// 返回分词相对于前一个分词的位置增量，和Lucene分词流的位置增量相同
+//
+// 通常为1。过滤器去掉的分词（比如StopWordFilter去掉的停用词）仍然占用位置，
+// 其后第一个保留的分词的位置增量加上被去掉的分词数，这样短语查询"中国的人口"
+// 去掉"的"后仍然不会匹配到相邻的"中国人口"。
+func (s *Segment) PositionIncrement() int {
+	return 1 + s.positionGap
+}
+
+// 带有行列位置的分词，见Segmenter.SegmentWithPosition
+type PositionedSegment struct {
+	Segment
+
+	// 分词起始位置所在的行，从1开始
+	line int
+
+	// 分词起始位置所在的列，以字符（Unicode码点）计，从1开始
+	column int
+}
+
+// 返回分词起始位置所在的行，从1开始
+func (s *PositionedSegment) Line() int {
+	return s.line
+}
+
+// 返回分词起始位置所在的列，以字符计，从1开始
+func (s *PositionedSegment) Column() int {
+	return s.column
+}
+
+// 带有拼音标注的分词，见Segmenter.AnnotatePinyin
+type AnnotatedSegment struct {
+	Segment
+
+	// 带声调数字的拼音，音节之间用空格分隔，比如"zhong1 guo2"
+	pinyin string
+}
+
+// 返回分词的拼音，音节之间用空格分隔
+func (s *AnnotatedSegment) Pinyin() string {
+	return s.pinyin
+}
+
+// 带有词性等分词信息的分词，见Segmenter.SegmentPOS
+type TaggedSegment struct {
+	Segment
+}
+
+// 返回分词的词性
+func (s *TaggedSegment) POS() string {
+	return s.token.pos
+}
+
+// 返回分词的词频，伪分词为1
+func (s *TaggedSegment) Frequency() int {
+	return s.token.frequency
+}
+
+// 返回分词的路径长度，即log2(总词频/该分词词频)，强制分词为一个很大的负数
+func (s *TaggedSegment) Distance() float32 {
+	return s.token.distance
+}
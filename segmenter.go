@@ -3,21 +3,158 @@ package sego
 
 import (
 	"bufio"
-	"log"
-	"math"
+	"errors"
+	"fmt"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"unicode"
 	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 const (
 	minTokenFrequency = 2 // 仅从字典文件中读取大于等于此频率的分词
 )
 
+// 输入文本不是合法的UTF8编码
+var ErrInvalidUTF8 = errors.New("sego: 非法的UTF8文本")
+
+// 输入文本中有无法按指定编码转换的字节
+var ErrInvalidEncoding = errors.New("sego: 无法转换的字节")
+
+// 分词时发生了内部错误（panic），比如在词典关闭之后分词
+var ErrSegmentPanic = errors.New("sego: 分词时发生内部错误")
+
 // 分词器结构体
 type Segmenter struct {
 	dict *Dictionary
+
+	// 非UTF8编码输入中无法转换的字节的处理方式，见SegmentGBK
+	decodeErrorMode DecodeErrorMode
+
+	// 分词前是否做Unicode规范化以及规范化的形式，见WithUnicodeNormalization
+	unicodeNormalization bool
+	normForm             norm.Form
+
+	// 分词前是否将全角字母和数字转换为半角，见WithFullWidthNormalization
+	fullWidthNormalization bool
+
+	// 分词前是否按词典的异体字映射表转换输入，见WithVariantNormalization
+	variantNormalization bool
+
+	// 划分字元时是否将非ASCII字母也转换为小写，见WithUnicodeLowercase
+	unicodeLowercase bool
+
+	// 划分字元时使用的边界策略，为nil时使用默认策略，见WithBoundaryPolicy
+	boundaryPolicy BoundaryPolicy
+
+	// 是否不合并连续的拉丁字母和数字，每个字符各自成为一个字元，优先于
+	// boundaryPolicy，见WithGroupAlphanumeric
+	ungroupAlphanumeric bool
+
+	// 自定义的字元划分器，不为nil时优先于boundaryPolicy，见WithCharSplitter
+	charSplitter CharSplitter
+
+	// 搜索模式下进一步划分的分词的最少字元数，零表示默认值2，见WithSearchMinLength
+	searchMinLength int
+
+	// 分词的最多字元数，零表示不限制，见WithMaxTokenLength
+	maxTokenLength int
+
+	// 是否在载入词典时立即构建所有分词的子分词，见WithEagerSubSegments
+	eagerSubSegments bool
+
+	// 是否将连续相同的伪分词合并为一个分词，见WithCollapseRepeats
+	collapseRepeats bool
+
+	// SegmentQuantities中和数词合并的量词，为nil时使用常用量词表，见WithMeasureWords
+	measureWords map[string]bool
+
+	// 分词后使用的词干提取器，为nil时不处理，见SetStemmer
+	stemmer Stemmer
+
+	// 词典中没有的其他类别字元对应的伪分词的词性，为空时使用"x"，见SetUnknownPOS
+	unknownPOS string
+
+	// 词典允许使用的词性，为nil时不检查，见WithPOSVocabulary
+	posVocabulary map[string]bool
+
+	// 句子级的分词结果缓存，为nil时不缓存，见EnableSentenceCache
+	cache *sentenceCache
+
+	// 本次分词中分词路径长度的调整值，只在SegmentWithOverrides使用的拷贝中设置
+	overrides map[string]float32
+
+	// 本次分词使用的二元语法模型，只在SegmentBigram使用的拷贝中设置
+	bigram *BigramModel
+
+	// 自定义的日志，为nil时使用包的logger，见SetLogger
+	customLogger Logger
+
+	// 保护模式，匹配的文本不会被切分，见AddProtectPattern
+	protectPatterns []protectPattern
+}
+
+// 分词器选项，见NewSegmenter
+type Option func(*Segmenter)
+
+// 创建一个分词器并应用选项
+//
+// 零值的Segmenter可以直接使用，等价于不带任何选项的NewSegmenter()。
+func NewSegmenter(opts ...Option) *Segmenter {
+	seg := &Segmenter{}
+	for _, opt := range opts {
+		opt(seg)
+	}
+	return seg
+}
+
+// 设置搜索模式下进一步划分的分词的最少字元数，默认为2
+//
+// 比如设为3时两个字的词（包括整段文本只有两个字元的情况）在搜索模式下不再
+// 划分成单字。词典中分词的子分词在载入词典时构建，因此需要在载入词典之前设置。
+// n小于2时按2处理。
+func WithSearchMinLength(n int) Option {
+	return func(seg *Segmenter) {
+		seg.searchMinLength = n
+	}
+}
+
+// 限制词典中分词的最多字元数
+//
+// 词典中分词的最大长度决定了分词时每个字元处在前缀树中查找的窗口大小，一个
+// 异常长的分词会拖慢所有的分词。设置后LoadDictionary、AddToken和ForceWord
+// 忽略超过n个字元的分词（载入词典时输出调试日志），分词时的查找窗口也不超过
+// n。n小于等于零时不限制。
+func WithMaxTokenLength(n int) Option {
+	return func(seg *Segmenter) {
+		seg.maxTokenLength = n
+	}
+}
+
+// 设置是否在载入词典时立即构建所有分词的子分词
+//
+// 子分词只在搜索模式（见Token.Segments）中使用，默认在第一次用到时才构建，
+// 不使用搜索模式时可以省下大约一半的载入时间和内存。eager为true时在载入词典
+// 和重建词典时构建所有子分词，之后的搜索模式分词没有额外的开销。需要在载入
+// 词典之前设置。
+func WithEagerSubSegments(eager bool) Option {
+	return func(seg *Segmenter) {
+		seg.eagerSubSegments = eager
+	}
+}
+
+// 判断分词是否超过了WithMaxTokenLength设置的长度
+func (seg *Segmenter) tooLong(words []Text) bool {
+	return seg.maxTokenLength > 0 && len(words) > seg.maxTokenLength
+}
+
+// 返回搜索模式下进一步划分的分词的最少字元数
+func (seg *Segmenter) minSearchLength() int {
+	return maxInt(seg.searchMinLength, 2)
 }
 
 // 该结构体用于记录Viterbi算法中某字元处的向前分词跳转信息
@@ -31,77 +168,231 @@ func (seg *Segmenter) Dictionary() *Dictionary {
 	return seg.dict
 }
 
+// 判断分词器是否已经载入了非空的词典，可以用于健康检查和初始化时的断言
+//
+// 没有载入词典、词典为空或者已经调用了Close时返回false。
+func (seg *Segmenter) IsReady() bool {
+	return seg.dict != nil && seg.dict.NumTokens() > 0
+}
+
 // 从字符串中载入词典
 //
 // 词典的格式为（每个分词一行）：
 //
 //	分词文本 频率 词性
+//
+// 一个词有多个词性时词性字段可以写成逗号分隔的列表，每个词性后面可以用冒号
+// 跟上权重，比如"研究 100 vn:0.7,n:0.3"，见Token.POSTags。
+// 第一个非空白字符为#的行是注释，和空行一样被忽略。开头的UTF-8 BOM会被去掉。
 func (seg *Segmenter) LoadDictionary(content string) {
-	seg.dict = NewDictionary()
+	seg.loadDictionary(content, nil)
+}
 
-	reader := bufio.NewReader(strings.NewReader(content))
-	var text string
-	var freqText string
-	var frequency int
-	var pos string
+func (seg *Segmenter) loadDictionary(content string, progress func(LoadProgress)) {
+	seg.dict = seg.newDictionary()
+	seg.cache.clear()
 
-	for {
+	reader := bufio.NewReader(strings.NewReader(strings.TrimPrefix(content, utf8BOM)))
+	linesSkipped := 0
+	linesRead := 0
+	for lineNumber := 1; ; lineNumber++ {
 		line, err := reader.ReadString('\n')
 		if err != nil && len(line) == 0 {
 			break
 		}
-		fields := strings.Fields(line)
-		if len(fields) < 2 {
-			continue
+		linesRead = lineNumber
+		if progress != nil && lineNumber%progressInterval == 0 {
+			progress(LoadProgress{Stage: LoadStageParse, Lines: lineNumber})
 		}
-		text = fields[0]
-		freqText = fields[1]
-		if len(fields) >= 3 {
-			pos = fields[2]
-		} else {
-			pos = ""
+		token, ok, blank := seg.dictionaryLineToken(line, lineNumber)
+		if !ok {
+			if !blank {
+				linesSkipped++
+			}
+			continue
 		}
+		seg.dict.addToken(token)
+	}
 
-		frequency, err = strconv.Atoi(freqText)
-		if err != nil || frequency < minTokenFrequency {
-			continue
+	// 计算路径值并构建子分词
+	var rebuildProgress func(int)
+	if progress != nil {
+		if linesRead%progressInterval != 0 {
+			progress(LoadProgress{Stage: LoadStageParse, Lines: linesRead})
 		}
+		rebuildProgress = func(tokens int) {
+			progress(LoadProgress{Stage: LoadStageRebuild, Lines: linesRead, Tokens: tokens})
+		}
+	}
+	seg.dict.rebuildLock.Lock()
+	seg.dict.rebuild(rebuildProgress)
+	seg.dict.rebuildLock.Unlock()
+
+	seg.logInfo("sego词典字符串载入完毕",
+		"tokens_loaded", seg.dict.NumTokens(), "lines_skipped", linesSkipped)
+	if progress != nil {
+		progress(LoadProgress{Stage: LoadStageDone, Lines: linesRead, Tokens: seg.dict.NumTokens()})
+	}
+}
 
-		words := splitTextToWords([]byte(text))
-		token := Token{text: words, frequency: frequency, pos: pos}
-		seg.dict.addToken(token)
+// 将词典中的一行解析为要载入的分词
+//
+// 该行不应载入时ok为false，其中空行和注释行的blank为true，其他原因（格式错误、
+// 词性不在词性表中、分词过长）输出日志。
+func (seg *Segmenter) dictionaryLineToken(line string, lineNumber int) (token Token, ok bool, blank bool) {
+	entry, issue := parseDictionaryLine(line)
+	if issue == dictLineBlank {
+		return token, false, true
+	}
+	if issue != dictLineOK {
+		seg.logDebug("sego词典跳过一行", "line", lineNumber, "text", strings.TrimSpace(line))
+		return token, false, false
+	}
+
+	if !seg.knownPOSTags(entry) {
+		seg.logError("sego词典中的词性不在词性表中", "line", lineNumber, "text", entry.text, "pos", entry.pos)
+		return token, false, false
 	}
 
-	// 计算路径值
-	logTotalFrequency := float32(math.Log2(float64(seg.dict.totalFrequency)))
-	for i := range seg.dict.tokens {
-		token := &seg.dict.tokens[i]
-		token.distance = logTotalFrequency - float32(math.Log2(float64(token.frequency)))
+	words := seg.splitText(seg.normalizeText([]byte(entry.text)))
+	if seg.tooLong(words) {
+		seg.logDebug("sego词典跳过过长的分词", "line", lineNumber, "text", entry.text, "length", len(words))
+		return token, false, false
 	}
+	return Token{text: words, frequency: entry.frequency, pos: entry.pos, posTags: entry.posTags}, true, false
+}
 
-	// 构建子分词（搜索模式用）
-	for i := range seg.dict.tokens {
-		token := &seg.dict.tokens[i]
-		segments := seg.segmentWords(token.text, true)
+// 构建分词的子分词（搜索模式用），见Token.Segments
+func (seg *Segmenter) buildSubSegments(token *Token) {
+	segments := seg.segmentWords(token.text, true)
 
-		numTokensToAdd := 0
-		for iToken := 0; iToken < len(segments); iToken++ {
-			if len(segments[iToken].token.text) > 0 {
-				numTokensToAdd++
-			}
+	numTokensToAdd := 0
+	for iToken := 0; iToken < len(segments); iToken++ {
+		if len(segments[iToken].token.text) > 0 {
+			numTokensToAdd++
 		}
-		token.segments = make([]*Segment, numTokensToAdd)
+	}
+	token.segments = make([]*Segment, numTokensToAdd)
 
-		iSegmentsToAdd := 0
-		for iToken := 0; iToken < len(segments); iToken++ {
-			if len(segments[iToken].token.text) > 0 {
-				token.segments[iSegmentsToAdd] = &segments[iToken]
-				iSegmentsToAdd++
-			}
+	iSegmentsToAdd := 0
+	for iToken := 0; iToken < len(segments); iToken++ {
+		if len(segments[iToken].token.text) > 0 {
+			token.segments[iSegmentsToAdd] = &segments[iToken]
+			iSegmentsToAdd++
+		}
+	}
+}
+
+// 强制分词的路径长度，远小于任何不含强制分词的路径的总长度
+const forcedTokenDistance = -1e4
+
+// 加入一个强制分词
+//
+// 和词典中的普通分词不同，强制分词的路径长度被设为一个很大的负数，因此只要
+// 文本中出现该分词，最短路径就一定会包含它，而不论其他切分方式的词频有多高。
+// 两个强制分词重叠时选择能包含更多强制分词的切分。pos为空时沿用词典中已有
+// 的词性。
+//
+// 该函数需要在载入词典之后调用，且不能和分词并发调用。
+func (seg *Segmenter) ForceWord(text string, pos string) {
+	if seg.dict == nil {
+		seg.dict = seg.newDictionary()
+	}
+	words := seg.splitText(seg.normalizeText([]byte(text)))
+	if len(words) == 0 || seg.tooLong(words) || !seg.knownPOS(pos) {
+		return
+	}
+
+	if value, err := seg.dict.trie.Get(textSliceToBytes(words)); err == nil {
+		token := seg.dict.token(value)
+		token.forced = true
+		token.distance = forcedTokenDistance
+		if pos != "" {
+			token.pos = pos
 		}
+		seg.cache.clear()
+		return
+	}
+
+	seg.dict.addToken(Token{text: words, frequency: minTokenFrequency,
+		distance: forcedTokenDistance, pos: pos, forced: true})
+	seg.dict.markDirty()
+	seg.cache.clear()
+}
+
+// 向词典中加入一个分词，词典中已有该分词时不做任何事
+//
+// 分词文本按分词器的规范化和字元划分规则处理。加入后并不立即重新计算路径值
+// 和子分词（这需要遍历整个词典），而是将词典标记为需要重建，下次分词时自动
+// 调用Dictionary.Rebuild，因此批量加入分词的代价是线性的。也可以在批量加入后
+// 直接调用Rebuild。
+//
+// 该函数不能和分词并发调用。
+func (seg *Segmenter) AddToken(text string, frequency int, pos string) {
+	if seg.dict == nil {
+		seg.dict = seg.newDictionary()
+	}
+	words := seg.splitText(seg.normalizeText([]byte(text)))
+	if len(words) == 0 || frequency <= 0 || seg.tooLong(words) || !seg.knownPOS(pos) {
+		return
 	}
+	seg.dict.addToken(Token{text: words, frequency: frequency, pos: pos})
+	seg.dict.markDirty()
+	seg.cache.clear()
+}
+
+// UTF-8编码的BOM，有些编辑器会把它写在文件开头
+const utf8BOM = "\uFEFF"
+
+// 创建一个按分词器的选项构建子分词的词典
+func (seg *Segmenter) newDictionary() *Dictionary {
+	dict := NewDictionary()
+	dict.searchMinLength = seg.searchMinLength
+	dict.unknownPOS = seg.unknownPOS
+	dict.eagerSegments = seg.eagerSubSegments
+	return dict
+}
 
-	log.Println("sego词典字符串载入完毕")
+// 词典中一行对应的分词条目
+type dictEntry struct {
+	text      string
+	frequency int
+	pos       string
+	posTags   []POSWeight
+}
+
+// 解析词典中的一行
+//
+// 返回的DictIssueKind为dictLineOK时该行是一个应该载入的分词；空行和注释行
+// 返回dictLineBlank；其余的值说明该行为什么被忽略。
+//
+// Windows下编辑的词典以"\r\n"换行，行尾的"\r"会被去掉。
+func parseDictionaryLine(line string) (entry dictEntry, issue DictIssueKind) {
+	fields := strings.Fields(strings.TrimRight(line, "\r\n"))
+	if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+		return entry, dictLineBlank
+	}
+	if len(fields) < 2 {
+		return entry, DictIssueMalformed
+	}
+	entry.text = fields[0]
+	if len(fields) >= 3 {
+		var ok bool
+		entry.pos, entry.posTags, ok = parsePOSField(fields[2])
+		if !ok {
+			return entry, DictIssueMalformed
+		}
+	}
+
+	frequency, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return entry, DictIssueMalformed
+	}
+	entry.frequency = frequency
+	if frequency < minTokenFrequency {
+		return entry, DictIssueLowFrequency
+	}
+	return entry, dictLineOK
 }
 
 // 对文本分词
@@ -113,44 +404,254 @@ func (seg *Segmenter) LoadDictionary(content string) {
 // 输出：
 //
 //	[]Segment	划分的分词
+//
+// 输入中的非法UTF8字节不会报错，每个字节单独输出为一个词性为"err"的分词。
+// 还没有载入词典时不会出错，每个字元单独输出为一个伪分词。分词时
+// 发生内部错误（比如在Close之后分词）时不会panic，而是输出错误日志并返回空的
+// 结果，需要得到错误时使用SegmentSafe。
+//
+// 分词结果是确定的：多条最短路径长度相等时选择结束于同一字元的分词中文本按
+// 字典序较小的一个（见preferToken），不依赖于词典中分词的顺序，同一词典对同一
+// 文本每次都得到相同的结果。
 func (seg *Segmenter) Segment(bytes []byte) []Segment {
 	return seg.internalSegment(bytes, false)
 }
 
+// 对文本分词，结果写入out[:0]并返回
+//
+// 分词的过程和选项（句子缓存、保护模式、合并重复、词干提取等）和Segment完全
+// 相同，结果也相同，只是最终结果写入out：out的容量足够时不为结果分配新的数组，
+// 因此在循环中反复使用同一个out可以减少内存分配。返回的结果和out共享底层数组，
+// 下次调用时会被覆盖。分词中的Token指针指向分词器共享的词典，调用者不能修改。
+func (seg *Segmenter) SegmentInto(bytes []byte, out []Segment) []Segment {
+	segments, err := seg.segmentRecovered(bytes, false, out[:0])
+	if err != nil {
+		seg.logError(err.Error())
+		return out[:0]
+	}
+	return segments
+}
+
+// 对文本分词，和Segment不同的是先检查输入是否为合法的UTF8文本
+//
+// 输入包含非法UTF8字节时不分词，返回的错误包装了ErrInvalidUTF8并指明第一个
+// 非法字节的位置。分词时发生内部错误时返回包装了ErrSegmentPanic的错误。
+func (seg *Segmenter) SegmentSafe(bytes []byte) ([]Segment, error) {
+	if !utf8.Valid(bytes) {
+		return nil, fmt.Errorf("%w（字节位置%d）", ErrInvalidUTF8, invalidUTF8Offset(bytes))
+	}
+	return seg.segmentRecovered(bytes, false, nil)
+}
+
+// 对文本分词，只匹配不超过maxLen个字元的分词
+//
+// 和WithMaxTokenLength不同，这里的限制只对本次分词有效，不影响词典，可以用于
+// 需要较粗切分的场合。比如maxLen为2时词典中的"中华人民共和国"不会被匹配，文本
+// 按更短的分词切分；没有更短的分词时切成单字伪分词。分词器本身也设置了
+// WithMaxTokenLength时取两者中较小的。maxLen小于等于零时和Segment相同。
+//
+// 该函数不使用句子缓存，可以和其他分词并发调用。
+func (seg *Segmenter) SegmentMaxLen(bytes []byte, maxLen int) []Segment {
+	if maxLen <= 0 {
+		return seg.internalSegment(bytes, false)
+	}
+	limited := *seg
+	limited.cache = nil
+	if limited.maxTokenLength <= 0 || maxLen < limited.maxTokenLength {
+		limited.maxTokenLength = maxLen
+	}
+	return limited.internalSegment(bytes, false)
+}
+
+// 对多行文本分词，并给出每个分词起始位置所在的行和列
+//
+// 分词结果和Segment相同（包括缓存和分词之后的处理）。行以'\n'分隔，行和列都
+// 从1开始，列以字符（Unicode码点）计。
+func (seg *Segmenter) SegmentWithPosition(bytes []byte) []PositionedSegment {
+	segments := seg.internalSegment(bytes, false)
+	output := make([]PositionedSegment, len(segments))
+
+	line := 1
+	lineRuneStart := 0
+	runePosition := 0
+	bytePosition := 0
+	for i, s := range segments {
+		// 扫描上一个分词起始位置到本分词起始位置之间的换行符
+		for bytePosition < s.start {
+			r, size := utf8.DecodeRune(bytes[bytePosition:])
+			bytePosition += size
+			runePosition++
+			if r == '\n' {
+				line++
+				lineRuneStart = runePosition
+			}
+		}
+		output[i].Segment = s
+		output[i].line = line
+		output[i].column = s.runeStart - lineRuneStart + 1
+	}
+	return output
+}
+
+// 并行地对多段文本分词
+//
+// 输入参数：
+//
+//	inputs	多段UTF8文本
+//	workers	并发的goroutine数目，小于等于零时使用runtime.GOMAXPROCS(0)
+//
+// 输出：
+//
+//	[][]Segment	和inputs一一对应的分词结果
+//
+// 载入词典后分词器是只读的，所有goroutine共享同一个Segmenter。
+func (seg *Segmenter) SegmentBatch(inputs [][]byte, workers int) [][]Segment {
+	output := make([][]Segment, len(inputs))
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = minInt(workers, len(inputs))
+
+	tasks := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for index := range tasks {
+				output[index] = seg.internalSegment(inputs[index], false)
+			}
+		}()
+	}
+	for index := range inputs {
+		tasks <- index
+	}
+	close(tasks)
+	wg.Wait()
+	return output
+}
+
 func (seg *Segmenter) InternalSegment(bytes []byte, searchMode bool) []Segment {
 	return seg.internalSegment(bytes, searchMode)
 }
 
 // 释放资源
+//
+// 关闭后分词器回到没有载入词典的状态：Segment等函数每个字元输出一个伪分词，
+// 不会panic。Close可以重复调用，关闭后可以再用LoadDictionary载入词典继续使用。
+// Close不能和分词并发调用，关闭前通过Dictionary取得的词典也不能再使用。
 func (seg *Segmenter) Close() {
 	if seg.dict != nil {
 		seg.dict.Close()
+		seg.dict = nil
 	}
+	seg.cache.clear()
 }
 
+// 分词发生内部错误时输出错误日志并返回空的结果，而不是让调用者崩溃
 func (seg *Segmenter) internalSegment(bytes []byte, searchMode bool) []Segment {
+	segments, err := seg.segmentRecovered(bytes, searchMode, nil)
+	if err != nil {
+		seg.logError(err.Error())
+		return []Segment{}
+	}
+	return segments
+}
+
+// 对文本分词，将分词过程中的panic转换为包装了ErrSegmentPanic的错误
+//
+// out不为nil时结果写入out[:0]，见SegmentInto。
+func (seg *Segmenter) segmentRecovered(bytes []byte, searchMode bool, out []Segment) (segments []Segment, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			segments = nil
+			err = fmt.Errorf("%w：%v", ErrSegmentPanic, r)
+		}
+	}()
+
+	if seg.cache == nil {
+		return seg.segmentBytes(bytes, searchMode, out), nil
+	}
+	key := cacheKey(bytes, searchMode)
+	if segments, ok := seg.cache.get(key, out); ok {
+		return segments, nil
+	}
+	segments = seg.segmentBytes(bytes, searchMode, out)
+	seg.cache.put(key, segments)
+	return segments, nil
+}
+
+// 规范化文本后分词，并按选项做分词之后的处理，out不为nil时结果写入out[:0]
+//
+// 返回的分词位置是相对于bytes的，见remapSegments。
+func (seg *Segmenter) segmentBytes(bytes []byte, searchMode bool, out []Segment) []Segment {
+	text, spans := seg.normalizeTextWithSpans(bytes)
+	segments := seg.segmentText(text, searchMode, out)
+	if seg.collapseRepeats {
+		segments = collapseRepeats(segments)
+	}
+	if seg.stemmer != nil {
+		segments = seg.stemSegments(segments)
+	}
+	if spans != nil {
+		segments = remapSegments(segments, spans, bytes)
+	}
+	return segments
+}
+
+// 对已经规范化的文本分词，out不为nil时结果写入out[:0]
+func (seg *Segmenter) segmentText(bytes []byte, searchMode bool, out []Segment) []Segment {
 	// 处理特殊情况
 	if len(bytes) == 0 {
-		return []Segment{}
+		if out == nil {
+			return []Segment{}
+		}
+		return out[:0]
+	}
+	seg.dict.rebuildIfDirty()
+
+	// 有保护模式时保护区间之外的文本分段处理
+	if len(seg.protectPatterns) > 0 {
+		return seg.segmentProtected(bytes, searchMode, out)
 	}
 
 	// 划分字元
-	text := splitTextToWords(bytes)
+	text := seg.splitText(bytes)
 
-	return seg.segmentWords(text, searchMode)
+	return seg.segmentWordsInto(text, searchMode, out)
 }
 
 func (seg *Segmenter) segmentWords(text []Text, searchMode bool) []Segment {
-	// 搜索模式下该分词已无继续划分可能的情况
-	if searchMode && len(text) == 1 {
-		return []Segment{}
+	return seg.segmentWordsInto(text, searchMode, nil)
+}
+
+// 和segmentWords相同，但结果写入out[:0]，out的容量不够时才重新分配
+func (seg *Segmenter) segmentWordsInto(text []Text, searchMode bool, out []Segment) []Segment {
+	// 搜索模式下该分词已无继续划分可能或者不需要继续划分的情况
+	if searchMode && len(text) < seg.minSearchLength() {
+		return out[:0]
 	}
+	if seg.bigram != nil && !searchMode {
+		return segmentsFromJumpers(seg.computeBigramJumpers(text), out)
+	}
+	return segmentsFromJumpers(seg.computeJumpers(text, searchMode), out)
+}
 
+// 用Viterbi算法计算每个字元处的最短路径和向前跳转信息
+func (seg *Segmenter) computeJumpers(text []Text, searchMode bool) []jumper {
 	// jumpers定义了每个字元处的向前跳转信息，包括这个跳转对应的分词，
 	// 以及从文本段开始到该字元的最短路径值
 	jumpers := make([]jumper, len(text))
 
-	tokens := make([]*Token, seg.dict.maxTokenLength)
+	// 没有载入词典时每个字元都是伪分词
+	maxTokenLength := 0
+	if seg.dict != nil {
+		maxTokenLength = seg.dict.maxTokenLength
+	}
+	if seg.maxTokenLength > 0 {
+		maxTokenLength = minInt(maxTokenLength, seg.maxTokenLength)
+	}
+	tokens := make([]*Token, maxTokenLength)
 	for current := 0; current < len(text); current++ {
 		// 找到前一个字元处的最短路径，以便计算后续路径值
 		var baseDistance float32
@@ -162,64 +663,93 @@ func (seg *Segmenter) segmentWords(text []Text, searchMode bool) []Segment {
 		}
 
 		// 寻找所有以当前字元开头的分词
-		numTokens := seg.dict.lookupTokens(
-			text[current:minInt(current+seg.dict.maxTokenLength, len(text))], tokens)
+		numTokens := 0
+		if maxTokenLength > 0 {
+			numTokens = seg.dict.lookupTokens(
+				text[current:minInt(current+maxTokenLength, len(text))], tokens)
+		}
 
 		// 对所有可能的分词，更新分词结束字元处的跳转信息
 		for iToken := 0; iToken < numTokens; iToken++ {
 			location := current + len(tokens[iToken].text) - 1
 			if !searchMode || current != 0 || location != len(text)-1 {
-				updateJumper(&jumpers[location], baseDistance, tokens[iToken])
+				updateJumper(&jumpers[location], baseDistance+seg.overrideDelta(tokens[iToken]), tokens[iToken])
 			}
 		}
 
-		// 当前字元没有对应分词时补加一个伪分词
+		// 当前字元没有对应分词时补加一个伪分词，非法UTF8字节的词性标注为"err"
 		if numTokens == 0 || len(tokens[0].text) > 1 {
-			updateJumper(&jumpers[current], baseDistance,
-				&Token{text: []Text{text[current]}, frequency: 1, distance: 32, pos: "x"})
+			kind := pseudoKind(text[current])
+			token := &Token{text: []Text{text[current]}, frequency: 1, distance: 32,
+				pos: pseudoPOS(text[current], kind, seg.unknownPOS), kind: kind}
+			updateJumper(&jumpers[current], baseDistance+seg.overrideDelta(token), token)
 		}
 	}
+	return jumpers
+}
 
+// 从最后一个字元处的跳转信息向前回溯得到分词结果，结果写入out[:0]
+func segmentsFromJumpers(jumpers []jumper, out []Segment) []Segment {
 	// 从后向前扫描第一遍得到需要添加的分词数目
 	numSeg := 0
-	for index := len(text) - 1; index >= 0; {
+	for index := len(jumpers) - 1; index >= 0; {
 		location := index - len(jumpers[index].token.text) + 1
 		numSeg++
 		index = location - 1
 	}
 
 	// 从后向前扫描第二遍添加分词到最终结果
-	outputSegments := make([]Segment, numSeg)
-	for index := len(text) - 1; index >= 0; {
+	var outputSegments []Segment
+	if cap(out) >= numSeg {
+		outputSegments = out[:numSeg]
+	} else {
+		outputSegments = make([]Segment, numSeg)
+	}
+	for index := len(jumpers) - 1; index >= 0; {
 		location := index - len(jumpers[index].token.text) + 1
 		numSeg--
-		outputSegments[numSeg].token = jumpers[index].token
+		// 词典中的分词都由addToken设置了所属词典，伪分词没有
+		token := jumpers[index].token
+		outputSegments[numSeg] = Segment{token: token, inDictionary: token.dict != nil}
 		index = location - 1
 	}
 
-	// 计算各个分词的字节位置
+	// 计算各个分词的字节位置和字符位置
 	bytePosition := 0
+	runePosition := 0
 	for iSeg := 0; iSeg < len(outputSegments); iSeg++ {
 		outputSegments[iSeg].start = bytePosition
+		outputSegments[iSeg].runeStart = runePosition
 		bytePosition += textSliceByteLength(outputSegments[iSeg].token.text)
+		runePosition += textSliceRuneCount(outputSegments[iSeg].token.text)
 		outputSegments[iSeg].end = bytePosition
+		outputSegments[iSeg].runeEnd = runePosition
 	}
 	return outputSegments
 }
 
 // 更新跳转信息:
-//  1. 当该位置从未被访问过时(jumper.minDistance为零的情况)，或者
-//  2. 当该位置的当前最短路径大于新的最短路径时
+//  1. 当该位置从未被访问过时(jumper.token为nil的情况)，或者
+//  2. 当该位置的当前最短路径大于新的最短路径时，或者
+//  3. 两者相等但新分词按preferToken更优时
 //
 // 将当前位置的最短路径值更新为baseDistance加上新分词的概率
 func updateJumper(jumper *jumper, baseDistance float32, token *Token) {
 	newDistance := baseDistance + token.distance
-	if jumper.minDistance == 0 || jumper.minDistance > newDistance {
+	if jumper.token == nil || jumper.minDistance > newDistance ||
+		(jumper.minDistance == newDistance && preferToken(token, jumper.token)) {
 		jumper.minDistance = newDistance
 		jumper.token = token
 	}
 }
 
+// 两条路径长度相等时判断结束于同一字元的分词a是否优于b，使分词结果不依赖于
+// 词典中分词的顺序：文本按字典序（UTF8字节序）较小的分词优先。结束于同一字元
+// 的不同分词文本一定不同，因此平局时的胜者总是唯一的。
+func preferToken(a, b *Token) bool {
+	return a.Text() < b.Text()
+}
+
 // 取两整数较小值
 func minInt(a, b int) int {
 	if a > b {
@@ -237,9 +767,14 @@ func maxInt(a, b int) int {
 }
 
 // 将文本划分成字元
+//
+// 非法的UTF8字节（比如被截断的多字节序列）每个字节单独成为一个字元，
+// 这样合法部分的字节位置不受影响。
 func splitTextToWords(text Text) []Text {
 	output := make([]Text, 0, len(text)/3)
 	current := 0
+	// 初始状态视为处在一个从0开始的空字母数字串中：文本以中日韩文字开头时
+	// 该空串被丢弃，以字母数字开头时该串从0开始延续
 	inAlphanumeric := true
 	alphanumericStart := 0
 	for current < len(text) {
@@ -253,7 +788,7 @@ func splitTextToWords(text Text) []Text {
 		} else {
 			if inAlphanumeric {
 				inAlphanumeric = false
-				if current != 0 {
+				if current > alphanumericStart {
 					output = append(output, toLower(text[alphanumericStart:current]))
 				}
 			}
@@ -263,17 +798,46 @@ func splitTextToWords(text Text) []Text {
 	}
 
 	// 处理最后一个字元是英文的情况
-	if inAlphanumeric {
-		if current != 0 {
-			output = append(output, toLower(text[alphanumericStart:current]))
-		}
+	if inAlphanumeric && current > alphanumericStart {
+		output = append(output, toLower(text[alphanumericStart:current]))
 	}
 
 	return output
 }
 
+// 判断字元是否为splitTextToWords从非法UTF8序列中切出的单个字节
+func isInvalidUTF8Word(word Text) bool {
+	return len(word) == 1 && word[0] >= utf8.RuneSelf
+}
+
+// 返回文本中第一个非法UTF8字节的位置，文本合法时返回-1
+func invalidUTF8Offset(text []byte) int {
+	for current := 0; current < len(text); {
+		r, size := utf8.DecodeRune(text[current:])
+		if r == utf8.RuneError && size == 1 {
+			return current
+		}
+		current += size
+	}
+	return -1
+}
+
 // 将英文词转化为小写
+//
+// 文本中没有大写字母时（比如纯数字或已经是小写）直接返回输入，不做拷贝，
+// 因此返回值可能和输入共享底层数组。
 func toLower(text []byte) []byte {
+	hasUpper := false
+	for _, t := range text {
+		if t >= 'A' && t <= 'Z' {
+			hasUpper = true
+			break
+		}
+	}
+	if !hasUpper {
+		return text
+	}
+
 	output := make([]byte, len(text))
 	for i, t := range text {
 		if t >= 'A' && t <= 'Z' {
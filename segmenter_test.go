@@ -1,6 +1,14 @@
 package sego
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -72,3 +80,615 @@ func TestLargeDictionary(t *testing.T) {
 	expect(t, "中华/nz 人民/n 共和/nz 国/n 共和国/ns 人民共和国/nt 中华人民共和国/ns 中央/n 人民/n 政府/n 人民政府/nt 中央人民政府/nt 中华人民共和国中央人民政府/nt ", SegmentsToString(prodSeg.Segment(
 		[]byte("中华人民共和国中央人民政府")), true))
 }
+
+func TestSegmentInvalidUTF8(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n")
+
+	// "有"的UTF8编码为"\xe6\x9c\x89"，这里截去最后一个字节
+	segments := seg.Segment([]byte("中国\xe6\x9c人口"))
+	expect(t, "4", len(segments))
+	expect(t, "ns err err n", segments[0].token.pos+" "+segments[1].token.pos+" "+
+		segments[2].token.pos+" "+segments[3].token.pos)
+	expect(t, "6", segments[1].start)
+	expect(t, "7", segments[1].end)
+	expect(t, "7", segments[2].start)
+	expect(t, "8", segments[2].end)
+	expect(t, "8", segments[3].start)
+	expect(t, "14", segments[3].end)
+
+	// 截断的序列位于文本末尾
+	segments = seg.Segment([]byte("人口\xe4\xb8"))
+	expect(t, "3", len(segments))
+	expect(t, "6", segments[1].start)
+	expect(t, "err", segments[2].token.pos)
+	expect(t, "8", segments[2].end)
+
+	// 截断的序列位于英文之后
+	segments = seg.Segment([]byte("abc\xe4中国"))
+	expect(t, "abc/x \xe4/err 中国/ns ", SegmentsToString(segments, false))
+	expect(t, "3", segments[1].start)
+	expect(t, "4", segments[1].end)
+
+	// 合法的U+FFFD字符仍然是普通的伪分词
+	segments = seg.Segment([]byte("�"))
+	expect(t, "x", segments[0].token.pos)
+}
+
+func TestSegmentRuneOffsets(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n")
+
+	text := []byte("中国有13亿人口")
+	segments := seg.Segment(text)
+	expect(t, "中国/ns 有/x 13/m 亿/x 人口/n ", SegmentsToString(segments, false))
+	expect(t, "0 2", fmt.Sprint(segments[0].RuneStart(), segments[0].RuneEnd()))
+	expect(t, "2 3", fmt.Sprint(segments[1].RuneStart(), segments[1].RuneEnd()))
+	expect(t, "3 5", fmt.Sprint(segments[2].RuneStart(), segments[2].RuneEnd()))
+	expect(t, "5 6", fmt.Sprint(segments[3].RuneStart(), segments[3].RuneEnd()))
+	expect(t, "6 8", fmt.Sprint(segments[4].RuneStart(), segments[4].RuneEnd()))
+	for _, s := range segments {
+		expect(t, fmt.Sprint(s.RuneStart()), ByteOffsetToRuneOffset(text, s.Start()))
+		expect(t, fmt.Sprint(s.RuneEnd()), ByteOffsetToRuneOffset(text, s.End()))
+	}
+}
+
+func TestSegmentWithPosition(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n")
+
+	segments := seg.SegmentWithPosition([]byte("中国人口\nab 中国\n\n人口"))
+	output := ""
+	for _, s := range segments {
+		output += fmt.Sprintf("%s:%d:%d ", s.Token().Text(), s.Line(), s.Column())
+	}
+	expect(t, "中国:1:1 人口:1:3 \n:1:5 ab:2:1  :2:3 中国:2:4 \n:2:6 \n:3:1 人口:4:1 ", output)
+
+	// 和Segment一样做分词之后的处理
+	processed := NewSegmenter(WithCollapseRepeats(true))
+	processed.LoadDictionary("中国 10 ns\nrunning 10 v\n")
+	processed.SetStemmer(testStemmer{})
+	text := []byte("running！！！\n中国")
+	segments = processed.SegmentWithPosition(text)
+	output = ""
+	for _, s := range segments {
+		output += fmt.Sprintf("%s:%d:%d ", s.Token().Text(), s.Line(), s.Column())
+	}
+	expect(t, "runn:1:1 ！！！:1:8 \n:1:11 中国:2:1 ", output)
+	expect(t, SegmentsToString(processed.Segment(text), false), SegmentsToString(segmentsOf(segments), false))
+}
+
+// 去掉行列位置
+func segmentsOf(positioned []PositionedSegment) []Segment {
+	segments := make([]Segment, len(positioned))
+	for i, s := range positioned {
+		segments[i] = s.Segment
+	}
+	return segments
+}
+
+func TestSegmentBatch(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n")
+
+	inputs := [][]byte{}
+	for i := 0; i < 100; i++ {
+		inputs = append(inputs, []byte(fmt.Sprintf("中国%d人口", i)))
+	}
+	for _, workers := range []int{0, 1, 4, 200} {
+		output := seg.SegmentBatch(inputs, workers)
+		expect(t, "100", len(output))
+		for i, segments := range output {
+			expect(t, fmt.Sprintf("中国/ns %d/m 人口/n ", i), SegmentsToString(segments, false))
+		}
+	}
+	expect(t, "0", len(seg.SegmentBatch(nil, 4)))
+}
+
+func TestSegmentSafe(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n")
+
+	segments, err := seg.SegmentSafe([]byte("中国人口"))
+	expect(t, "<nil>", err)
+	expect(t, "中国/ns 人口/n ", SegmentsToString(segments, false))
+
+	segments, err = seg.SegmentSafe([]byte("中国\xe4\xb8人口"))
+	expect(t, "true", errors.Is(err, ErrInvalidUTF8))
+	expect(t, "sego: 非法的UTF8文本（字节位置6）", err)
+	expect(t, "0", len(segments))
+}
+
+func TestToLower(t *testing.T) {
+	expect(t, "github2018", string(toLower([]byte("GitHub2018"))))
+
+	// 不需要转换时返回原数组
+	text := []byte("20180614")
+	output := toLower(text)
+	expect(t, "true", &output[0] == &text[0])
+}
+
+func BenchmarkToLowerDigits(b *testing.B) {
+	text := []byte("13800138000 2018年6月14日 YZL-1806052 81%(含)-90%(含) 18-24周岁")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		splitTextToWords(text)
+	}
+}
+
+func TestForceWord(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("南京 50 ns\n南京市 100 ns\n市长 200 n\n长江大桥 100 ns\n江大桥 2 nr\n")
+	text := []byte("南京市长江大桥")
+	expect(t, "南京市/ns 长江大桥/ns ", SegmentsToString(seg.Segment(text), false))
+
+	// 已有的分词，总词频更高的切分也不能胜过强制分词
+	seg.ForceWord("市长", "")
+	expect(t, "南京/ns 市长/n 江大桥/nr ", SegmentsToString(seg.Segment(text), false))
+
+	// 词典中没有的分词
+	var seg2 Segmenter
+	seg2.LoadDictionary("南京 50 ns\n南京市 100 ns\n市长 200 n\n长江大桥 100 ns\n江大桥 2 nr\n")
+	seg2.ForceWord("长江", "ns")
+	segments := seg2.Segment(text)
+	expect(t, "南京市/ns 长江/ns 大/x 桥/x ", SegmentsToString(segments, false))
+	expect(t, "9", segments[1].start)
+	expect(t, "15", segments[1].end)
+	expect(t, "2", len(seg2.Segment([]byte("长江"))[0].token.Segments()))
+}
+
+func TestSegmentInto(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n")
+
+	out := make([]Segment, 0, 8)
+	segments := seg.SegmentInto([]byte("中国有十三亿人口"), out)
+	expect(t, "中国/ns 有/x 十/x 三/x 亿/x 人口/n ", SegmentsToString(segments, false))
+	expect(t, "true", &segments[0] == &out[:1][0])
+
+	// 复用上次的结果
+	segments = seg.SegmentInto([]byte("人口中国"), segments)
+	expect(t, "人口/n 中国/ns ", SegmentsToString(segments, false))
+	expect(t, "6", segments[1].start)
+	expect(t, "true", &segments[0] == &out[:1][0])
+
+	// 容量不够时重新分配
+	segments = seg.SegmentInto([]byte("中国有十三亿人口"), out[:0:1])
+	expect(t, "6", len(segments))
+	expect(t, "false", &segments[0] == &out[:1][0])
+
+	expect(t, "0", len(seg.SegmentInto(nil, out)))
+}
+
+func TestSegmentIntoMatchesSegment(t *testing.T) {
+	seg := NewSegmenter(WithCollapseRepeats(true))
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\nrunning 10 v\n")
+	seg.EnableURLProtection()
+	seg.SetStemmer(testStemmer{})
+	seg.EnableSentenceCache(1<<20, 0)
+
+	text := []byte("中国人口！！！见http://example.com，running")
+	expected := SegmentsToString(seg.Segment(text), false)
+	expect(t, "中国/ns 人口/n ！！！/w 见/x http://example.com/url ，/w runn/v ", expected)
+
+	out := make([]Segment, 0, 16)
+	for i := 0; i < 2; i++ {
+		// 第二次命中句子缓存
+		segments := seg.SegmentInto(text, out)
+		expect(t, expected, SegmentsToString(segments, false))
+		expect(t, "true", &segments[0] == &out[:1][0])
+	}
+
+	// 没有缓存时也使用传入的数组
+	seg.EnableSentenceCache(0, 0)
+	segments := seg.SegmentInto(text, out)
+	expect(t, expected, SegmentsToString(segments, false))
+	expect(t, "true", &segments[0] == &out[:1][0])
+}
+
+func TestAddToken(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n")
+	text := []byte("中国有十三亿人口")
+
+	seg.AddToken("十三亿", 10, "m")
+	seg.AddToken("有", 10, "v")
+	expect(t, "1", seg.dict.dirty)
+	expect(t, "0", seg.dict.tokens[2].distance)
+
+	// 分词时自动重建
+	expect(t, "中国/ns 有/v 十三亿/m 人口/n ", SegmentsToString(seg.Segment(text), false))
+	expect(t, "0", seg.dict.dirty)
+	expect(t, "2", seg.dict.tokens[2].distance)
+	expect(t, "3", len(seg.dict.tokens[2].Segments()))
+
+	seg.AddToken("亿人", 1000, "n")
+	seg.Dictionary().Rebuild()
+	expect(t, "0", seg.dict.dirty)
+	expect(t, "true", seg.dict.tokens[4].distance < 0.1)
+	expect(t, "true", seg.dict.tokens[2].distance > 6)
+
+	// 强制分词的路径值在重建后保持不变
+	seg.ForceWord("十三亿", "")
+	seg.AddToken("人口众多", 10, "l")
+	seg.Dictionary().Rebuild()
+	expect(t, "中国/ns 有/v 十三亿/m 人口/n ", SegmentsToString(seg.Segment(text), false))
+}
+
+func TestLoadDictionaryCommentsAndBOM(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("\uFEFF中国 10 ns\n" +
+		"# 常用词 100 n\n" +
+		"  #人口 10 n\n" +
+		"\n" +
+		"## 第二部分 ##\n" +
+		"人口 10 n\n")
+	expect(t, "2", len(seg.dict.tokens))
+	expect(t, "中国", seg.dict.tokens[0].Text())
+	expect(t, "中国/ns 人口/n ", SegmentsToString(seg.Segment([]byte("中国人口")), false))
+}
+
+func TestLoadDictionaryCRLF(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("\uFEFF中国 10 ns\r\n# 注释\r\n\r\n人口 10 n\r\n十三亿 10\r\n")
+	expect(t, "3", len(seg.dict.tokens))
+	expect(t, "中国", seg.dict.tokens[0].Text())
+	expect(t, "\"ns\"", fmt.Sprintf("%q", seg.dict.tokens[0].Pos()))
+	expect(t, "\"n\"", fmt.Sprintf("%q", seg.dict.tokens[1].Pos()))
+	expect(t, "\"\"", fmt.Sprintf("%q", seg.dict.tokens[2].Pos()))
+	expect(t, "中国/ns 十三亿/ 人口/n ", SegmentsToString(seg.Segment([]byte("中国十三亿人口")), false))
+
+	issues, err := ValidateDictionary("中国 10 ns\r\n中国 10 ns\r\n")
+	expect(t, "<nil>", err)
+	expect(t, "1", len(issues))
+}
+
+func TestSegmentWithoutDictionary(t *testing.T) {
+	var seg Segmenter
+	expect(t, "中/x 国/x 有/x 13/m 亿/x 人/x 口/x \xff/err ",
+		SegmentsToString(seg.Segment([]byte("中国有13亿人口\xff")), false))
+	expect(t, "", SegmentsToString(seg.Segment([]byte("")), false))
+	expect(t, "中/x 国/x ", SegmentsToString(seg.InternalSegment([]byte("中国"), true), true))
+
+	segments, jumpers := seg.SegmentDebug([]byte("中国"))
+	expect(t, "2", len(segments))
+	expect(t, "2", len(jumpers))
+}
+
+func TestSearchMinLength(t *testing.T) {
+	dictionary := "中华 10 nz\n人民 10 n\n共和国 10 ns\n中华人民共和国 10 ns\n"
+	text := []byte("中华人民共和国")
+
+	var seg Segmenter
+	seg.LoadDictionary(dictionary)
+	expect(t, "2", len(seg.dict.tokens[1].Segments()))
+	expect(t, "3", len(seg.dict.tokens[2].Segments()))
+	expect(t, "中华/nz 人民/n 共和国/ns ", SegmentsToString(seg.InternalSegment(text, true), false))
+
+	seg3 := NewSegmenter(WithSearchMinLength(3))
+	seg3.LoadDictionary(dictionary)
+	expect(t, "0", len(seg3.dict.tokens[1].Segments()))
+	expect(t, "3", len(seg3.dict.tokens[2].Segments()))
+	expect(t, "中华/nz 人民/n 共和国/ns ", SegmentsToString(seg3.InternalSegment(text, true), false))
+	expect(t, "0", len(seg3.InternalSegment([]byte("人民"), true)))
+	expect(t, "2", len(seg.InternalSegment([]byte("人民"), true)))
+
+	// AddToken加入的分词同样按设置构建子分词
+	seg3.AddToken("国家", 10, "n")
+	seg3.Segment([]byte("国家"))
+	expect(t, "0", len(seg3.dict.tokens[4].Segments()))
+}
+
+func TestSplitLeadingCharacter(t *testing.T) {
+	// 以字母开头、以中文开头、只有一个字母等情况下字元的边界都精确
+	expect(t, "a/中/国/", bytesToString(splitTextToWords([]byte("a中国"))))
+	expect(t, "ab/中/", bytesToString(splitTextToWords([]byte("AB中"))))
+	expect(t, "中/a/", bytesToString(splitTextToWords([]byte("中a"))))
+	expect(t, "中/ab/国/", bytesToString(splitTextToWords([]byte("中ab国"))))
+	expect(t, "a/", bytesToString(splitTextToWords([]byte("a"))))
+	expect(t, "7/", bytesToString(splitTextToWords([]byte("7"))))
+	expect(t, "中/", bytesToString(splitTextToWords([]byte("中"))))
+	expect(t, " /a/", bytesToString(splitTextToWords([]byte(" a"))))
+	expect(t, "0", len(splitTextToWords([]byte(""))))
+
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n")
+	offsets := func(text string) string {
+		output := ""
+		for _, s := range seg.Segment([]byte(text)) {
+			output += fmt.Sprintf("%s[%d:%d|%d:%d] ", s.Token().Text(), s.Start(), s.End(), s.RuneStart(), s.RuneEnd())
+		}
+		return output
+	}
+	expect(t, "a[0:1|0:1] 中国[1:7|1:3] ", offsets("a中国"))
+	expect(t, "abc[0:3|0:3] 中国[3:9|3:5] ", offsets("abc中国"))
+	expect(t, "中国[0:6|0:2] a[6:7|2:3] ", offsets("中国a"))
+	expect(t, "a[0:1|0:1] ", offsets("a"))
+	expect(t, "a[0:1|0:1] 中国[1:7|1:3] b[7:8|3:4] ", offsets("A中国B"))
+}
+
+func TestSegmentTieBreak(t *testing.T) {
+	// "中国|人"和"中|国人"的路径长度相等，结束于最后一个字元的分词"人"按字典序
+	// 小于"国人"
+	dictionaries := []string{
+		"中国 10 ns\n人 10 n\n中 10 f\n国人 10 n\n",
+		"国人 10 n\n中 10 f\n人 10 n\n中国 10 ns\n",
+	}
+	for _, dictionary := range dictionaries {
+		var seg Segmenter
+		seg.LoadDictionary(dictionary)
+		expect(t, "中国/ns 人/n ", SegmentsToString(seg.Segment([]byte("中国人")), false))
+	}
+
+	// 只比较文本，不考虑长度和词频
+	a := &Token{text: toWords("国", "人"), frequency: 20}
+	b := &Token{text: toWords("人"), frequency: 10}
+	c := &Token{text: toWords("入"), frequency: 30}
+	expect(t, "true", preferToken(b, a))
+	expect(t, "false", preferToken(a, b))
+	expect(t, "true", preferToken(b, c))
+	expect(t, "false", preferToken(b, b))
+}
+
+func TestSegmentDeterministic(t *testing.T) {
+	// 多种切分方式的路径长度相等，打乱词典顺序后结果不变
+	lines := []string{
+		"中国 10 ns", "人 10 n", "中 10 f", "国人 10 n", "国 10 n",
+		"人民 10 n", "民 10 n", "中国人 5 n", "中国人民 5 nt",
+	}
+	text := []byte("中国人民中国人中国")
+	var want string
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		random.Shuffle(len(lines), func(a, b int) { lines[a], lines[b] = lines[b], lines[a] })
+		var seg Segmenter
+		seg.LoadDictionary(strings.Join(lines, "\n"))
+		for j := 0; j < 3; j++ {
+			got := SegmentsToString(seg.Segment(text), true)
+			if want == "" {
+				want = got
+			}
+			expect(t, want, got)
+		}
+	}
+}
+
+func TestSegmentRecover(t *testing.T) {
+	var buf bytes.Buffer
+	var seg Segmenter
+	seg.SetLogger(NewStdLogger(log.New(&buf, "", 0)))
+	seg.LoadDictionary("中国 10 ns\n")
+	buf.Reset()
+
+	// 词典损坏时分词不会panic
+	seg.dict.trie = nil
+	expect(t, "0", len(seg.Segment([]byte("中国"))))
+	expect(t, "true", strings.HasPrefix(buf.String(), "[ERROR] sego: 分词时发生内部错误："))
+
+	segments, err := seg.SegmentSafe([]byte("中国"))
+	expect(t, "0", len(segments))
+	expect(t, "true", errors.Is(err, ErrSegmentPanic))
+}
+
+func TestMaxTokenLength(t *testing.T) {
+	dictionary := "中国 10 ns\n人口 10 n\n中华人民共和国中央人民政府 10 nt\n"
+	var seg Segmenter
+	seg.LoadDictionary(dictionary)
+	expect(t, "13", seg.dict.MaxTokenLength())
+
+	var buf bytes.Buffer
+	capped := NewSegmenter(WithMaxTokenLength(4))
+	capped.SetLogger(NewStdLogger(log.New(&buf, "", 0)))
+	capped.LoadDictionary(dictionary)
+	expect(t, "2", capped.dict.NumTokens())
+	expect(t, "2", capped.dict.MaxTokenLength())
+	expect(t, "[DEBUG] sego词典跳过过长的分词 line=3 text=中华人民共和国中央人民政府 length=13\n"+
+		"[INFO] sego词典字符串载入完毕 tokens_loaded=2 lines_skipped=1\n", buf.String())
+
+	capped.AddToken("十三亿人口", 10, "n")
+	capped.ForceWord("有十三亿人口", "")
+	expect(t, "2", capped.dict.NumTokens())
+	capped.AddToken("十三亿", 10, "m")
+	expect(t, "中国/ns 有/x 十三亿/m 人口/n ", SegmentsToString(capped.Segment([]byte("中国有十三亿人口")), false))
+
+	// 查找窗口不超过设置的长度
+	capped.dict.addToken(Token{text: toWords("有", "十", "三", "亿", "人"), frequency: 1000})
+	capped.dict.Rebuild()
+	expect(t, "中国/ns 有/x 十三亿/m 人口/n ", SegmentsToString(capped.Segment([]byte("中国有十三亿人口")), false))
+}
+
+func BenchmarkPunctuationHeavy(b *testing.B) {
+	// 大部分字元是词典中没有的标点和符号，每个位置都要查找一次前缀树
+	content, err := os.ReadFile("data/dictionary.txt")
+	if err != nil {
+		b.Fatal(err)
+	}
+	text := []byte(strings.Repeat("中国有十三亿人口……——《》「」【】！？；：“”‘’、，。·～＃＠（）①②③★☆※", 20))
+	SetLogger(nil)
+	var seg Segmenter
+	seg.LoadDictionary(string(content))
+	SetLogger(log.Default())
+
+	// 只计算每个字元处的前缀树查找
+	words := seg.splitText(text)
+	tokens := make([]*Token, seg.dict.maxTokenLength)
+	b.Run("lookup", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for current := range words {
+				seg.dict.lookupTokens(words[current:minInt(current+len(tokens), len(words))], tokens)
+			}
+		}
+	})
+	b.Run("segment", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			seg.Segment(text)
+		}
+	})
+}
+
+func BenchmarkMaxTokenLength(b *testing.B) {
+	// 一个异常长的分词使每个字元处的查找窗口都变大，文本中和它前缀相同的部分
+	// 每个字元处都要在前缀树中走得很深
+	dictionary := "中国 10 ns\n人口 10 n\n十三亿 10 m\n有 10 v\n长 10 a\n" +
+		strings.Repeat("长", 200) + " 2 x\n"
+	text := []byte(strings.Repeat("中国有十三亿人口"+strings.Repeat("长", 50), 10))
+	for _, maxTokenLength := range []int{0, 16} {
+		seg := NewSegmenter(WithMaxTokenLength(maxTokenLength))
+		SetLogger(nil)
+		seg.LoadDictionary(dictionary)
+		SetLogger(log.Default())
+		b.Run(fmt.Sprintf("max=%d", maxTokenLength), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				seg.Segment(text)
+			}
+		})
+	}
+}
+
+func TestSegmentMaxLen(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中华 10 nz\n人民 10 n\n共和国 10 ns\n中华人民共和国 10 ns\n共和 10 nz\n")
+	text := []byte("a中华人民共和国b")
+
+	expect(t, "a/x 中华人民共和国/ns b/x ", SegmentsToString(seg.SegmentMaxLen(text, 0), false))
+	expect(t, "a/x 中华/nz 人民/n 共和国/ns b/x ", SegmentsToString(seg.SegmentMaxLen(text, 4), false))
+	expect(t, "a/x 中华/nz 人民/n 共和/nz 国/x b/x ", SegmentsToString(seg.SegmentMaxLen(text, 2), false))
+
+	// 位置仍然是在原文本中的位置
+	output := ""
+	for _, s := range seg.SegmentMaxLen(text, 2) {
+		output += fmt.Sprintf("%d-%d/%d-%d ", s.Start(), s.End(), s.RuneStart(), s.RuneEnd())
+	}
+	expect(t, "0-1/0-1 1-7/1-3 7-13/3-5 13-19/5-7 19-22/7-8 22-23/8-9 ", output)
+
+	// 不影响之后的分词
+	expect(t, "a/x 中华人民共和国/ns b/x ", SegmentsToString(seg.Segment(text), false))
+}
+
+func TestSegmentBytes(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\ngithub 10 nz\n")
+	text := []byte("GitHub在中国")
+	segments := seg.Segment(text)
+
+	expect(t, "github", segments[0].Token().Text())
+	expect(t, "GitHub", segments[0].String(text))
+	expect(t, "中国", string(segments[2].Bytes(text)))
+
+	// 位置和文本不一致时返回空
+	expect(t, "", segments[2].String(text[:5]))
+	expect(t, "0", len(segments[2].Bytes(nil)))
+}
+
+func TestSegmentInDictionary(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\ngithub 10 nz\n")
+	segments := seg.Segment([]byte("GitHub在中国123"))
+	expect(t, "4", len(segments))
+	expect(t, "true", segments[0].InDictionary())
+	expect(t, "false", segments[1].InDictionary())
+	expect(t, "true", segments[2].InDictionary())
+	expect(t, "false", segments[3].InDictionary())
+
+	// 强制分词加入词典后也是词典分词
+	seg.ForceWord("在中", "p")
+	segments = seg.Segment([]byte("在中国"))
+	expect(t, "true", segments[0].InDictionary())
+	expect(t, "false", segments[1].InDictionary())
+}
+
+func TestIsReady(t *testing.T) {
+	var seg Segmenter
+	expect(t, "false", seg.IsReady())
+	seg.LoadDictionary("# 空词典\n")
+	expect(t, "false", seg.IsReady())
+	seg.LoadDictionary("中国 10 ns\n")
+	expect(t, "true", seg.IsReady())
+	seg.Close()
+	expect(t, "false", seg.IsReady())
+}
+
+func TestSegmentAfterClose(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n")
+	seg.EnableSentenceCache(1024, 0)
+	expect(t, "中国/ns 人口/n ", SegmentsToString(seg.Segment([]byte("中国人口")), false))
+
+	// 关闭后每个字元是一个伪分词，重复关闭没有问题
+	seg.Close()
+	seg.Close()
+	expect(t, "中/x 国/x 人/x 口/x ", SegmentsToString(seg.Segment([]byte("中国人口")), false))
+	expect(t, "中/x 国/x 人/x 口/x ", SegmentsToString(seg.Segment([]byte("中国人口")), true))
+	expect(t, "true", seg.Dictionary() == nil)
+
+	// 和零值的分词器一样可以加入分词或者重新载入词典
+	seg.AddToken("中国", 10, "ns")
+	expect(t, "中国/ns 人/x 口/x ", SegmentsToString(seg.Segment([]byte("中国人口")), false))
+	seg.LoadDictionary("中国人 10 n\n")
+	expect(t, "中国人/n 口/x ", SegmentsToString(seg.Segment([]byte("中国人口")), false))
+}
+
+func TestLazySubSegments(t *testing.T) {
+	dictionary := "中华 10 nz\n人民 10 n\n共和国 10 ns\n中华人民共和国 10 ns\n"
+	var lazy Segmenter
+	lazy.LoadDictionary(dictionary)
+	eager := NewSegmenter(WithEagerSubSegments(true))
+	eager.LoadDictionary(dictionary)
+
+	expect(t, "0", lazy.dict.tokens[3].segmentsBuilt)
+	expect(t, "1", eager.dict.tokens[3].segmentsBuilt)
+
+	// 并发调用时只构建一次
+	var wg sync.WaitGroup
+	results := make([]string, 8)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = SegmentsToString(lazy.Segment([]byte("中华人民共和国")), true)
+		}(i)
+	}
+	wg.Wait()
+	want := SegmentsToString(eager.Segment([]byte("中华人民共和国")), true)
+	expect(t, "中华/nz 人民/n 共和国/ns 中华人民共和国/ns ", want)
+	for _, result := range results {
+		expect(t, want, result)
+	}
+	expect(t, "1", lazy.dict.tokens[3].segmentsBuilt)
+
+	// 加入分词后子分词重新构建
+	lazy.AddToken("人民共和国", 100, "nt")
+	eager.AddToken("人民共和国", 100, "nt")
+	expect(t, "中华/nz 人民/n 共和国/ns 人民共和国/nt 中华人民共和国/ns ",
+		SegmentsToString(lazy.Segment([]byte("中华人民共和国")), true))
+	expect(t, SegmentsToString(eager.Segment([]byte("中华人民共和国")), true),
+		SegmentsToString(lazy.Segment([]byte("中华人民共和国")), true))
+}
+
+func BenchmarkLoadDictionary(b *testing.B) {
+	chars := []rune("中华人民共和国有十三亿人口长江大桥南京市的是了在不和有大这主中人上为们地个用工时要动国产以我到他会作来分生对于学下级就年阶义发成部民可出能方进同行面说种过命度革而多子后自社加小机也经力线本电高量长党得实家定深法表着水理化争现所二起政三好十战")
+	var builder strings.Builder
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 20000; i++ {
+		length := 2 + random.Intn(3)
+		for j := 0; j < length; j++ {
+			builder.WriteRune(chars[random.Intn(len(chars))])
+		}
+		fmt.Fprintf(&builder, " %d n\n", 2+random.Intn(1000))
+	}
+	dictionary := builder.String()
+
+	SetLogger(nil)
+	defer SetLogger(log.Default())
+	for _, eager := range []bool{false, true} {
+		b.Run(fmt.Sprintf("eager=%v", eager), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				seg := NewSegmenter(WithEagerSubSegments(eager))
+				seg.LoadDictionary(dictionary)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package sego
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// 将文本划分为句子
+//
+// 在"。！？!?"、换行符以及英文句点处断句，句末的结束符保留在句子中。连续的
+// 结束符（比如"？！"、"……"、"\n\n"）、紧随其后的后引号和后括号以及空格都归入
+// 前一个句子，因此所有句子按顺序拼接起来就是原文本。
+//
+// 英文句点后面紧跟字母或数字时不断句，比如"3.14"和"e.g"。
+//
+// 返回的句子直接引用输入的字节数组，不做拷贝。
+func SplitSentences(bytes []byte) [][]byte {
+	output := [][]byte{}
+	start := 0
+	current := 0
+	for current < len(bytes) {
+		r, size := utf8.DecodeRune(bytes[current:])
+		current += size
+		if !isSentenceTerminator(r, bytes[current:]) {
+			continue
+		}
+
+		// 吸收结束符之后的连续结束符、后引号和空格
+		for current < len(bytes) {
+			r, size = utf8.DecodeRune(bytes[current:])
+			if !isSentenceTerminator(r, bytes[current+size:]) && !isSentenceTrailer(r) {
+				break
+			}
+			current += size
+		}
+		output = append(output, bytes[start:current:current])
+		start = current
+	}
+
+	// 处理最后一个句子没有结束符的情况
+	if start < len(bytes) {
+		output = append(output, bytes[start:len(bytes):len(bytes)])
+	}
+	return output
+}
+
+// 判断字符r是否为句子结束符，next为r之后的文本
+func isSentenceTerminator(r rune, next []byte) bool {
+	switch r {
+	case '。', '！', '？', '!', '?', '\n', '…':
+		return true
+	case '.':
+		// 小数和缩写中的句点不断句
+		nextRune, _ := utf8.DecodeRune(next)
+		return len(next) == 0 || !(unicode.IsLetter(nextRune) || unicode.IsNumber(nextRune))
+	}
+	return false
+}
+
+// 判断字符r是否为应该归入前一个句子的后引号、后括号或空格
+func isSentenceTrailer(r rune) bool {
+	switch r {
+	case '”', '’', '」', '』', '）', ')', '"', '\'', ' ', '\t', '\r':
+		return true
+	}
+	return false
+}
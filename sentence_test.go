@@ -0,0 +1,40 @@
+package sego
+
+import (
+	"testing"
+)
+
+func sentencesToString(sentences [][]byte) (output string) {
+	for _, s := range sentences {
+		output += string(s) + "|"
+	}
+	return
+}
+
+func TestSplitSentences(t *testing.T) {
+	expect(t, "中国有十三亿人口。|你知道吗？|", sentencesToString(SplitSentences(
+		[]byte("中国有十三亿人口。你知道吗？"))))
+
+	expect(t, "真的吗？！|是的！|没有结束符|", sentencesToString(SplitSentences(
+		[]byte("真的吗？！是的！没有结束符"))))
+
+	expect(t, "他说：“好的。”|然后走了……|", sentencesToString(SplitSentences(
+		[]byte("他说：“好的。”然后走了……"))))
+
+	expect(t, "第一行\n\n|第二行\n|", sentencesToString(SplitSentences(
+		[]byte("第一行\n\n第二行\n"))))
+
+	expect(t, "Pi is 3.14 roughly. |Really? |Yes!|", sentencesToString(SplitSentences(
+		[]byte("Pi is 3.14 roughly. Really? Yes!"))))
+
+	expect(t, "See e.g.the docs.|", sentencesToString(SplitSentences(
+		[]byte("See e.g.the docs."))))
+
+	expect(t, "0", len(SplitSentences([]byte{})))
+
+	// 返回的句子引用原文本
+	text := []byte("一。二。")
+	sentences := SplitSentences(text)
+	sentences[1][0] = 'x'
+	expect(t, "一。x\xba\x8c。", string(text))
+}
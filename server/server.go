@@ -9,8 +9,8 @@ sego分词服务器同时提供了两种模式：
 		输出JSON格式：
 			{
 				segments:[
-					{"text":"服务器", "pos":"n"},
-					{"text":"指令", "pos":"n"},
+					{"text":"服务器", "pos":"n", "kind":"dictionary"},
+					{"text":"指令", "pos":"n", "kind":"dictionary"},
 					...
 				]
 			}
@@ -26,19 +26,21 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
-	"github.com/huichen/sego"
 	"io"
 	"log"
 	"net/http"
+	"os"
 	"runtime"
+
+	"github.com/Aopro7/sego"
 )
 
 var (
-	host      = flag.String("host", "", "HTTP服务器主机名")
-	port      = flag.Int("port", 8080, "HTTP服务器端口")
-	dict      = flag.String("dict", "../data/dictionary.txt", "词典文件")
+	host         = flag.String("host", "", "HTTP服务器主机名")
+	port         = flag.Int("port", 8080, "HTTP服务器端口")
+	dict         = flag.String("dict", "../data/dictionary.txt", "词典文件")
 	staticFolder = flag.String("static_folder", "static", "静态页面存放的目录")
-	segmenter = sego.Segmenter{}
+	segmenter    = sego.Segmenter{}
 )
 
 type JsonResponse struct {
@@ -46,8 +48,10 @@ type JsonResponse struct {
 }
 
 type Segment struct {
-	Text string `json:"text"`
-	Pos  string `json:"pos"`
+	Text         string    `json:"text"`
+	Pos          string    `json:"pos"`
+	Kind         sego.Kind `json:"kind"`
+	InDictionary bool      `json:"in_dictionary"`
 }
 
 func JsonRpcServer(w http.ResponseWriter, req *http.Request) {
@@ -63,7 +67,8 @@ func JsonRpcServer(w http.ResponseWriter, req *http.Request) {
 	// 整理为输出格式
 	ss := []*Segment{}
 	for _, segment := range segments {
-		ss = append(ss, &Segment{Text: segment.Token().Text(), Pos: segment.Token().Pos()})
+		ss = append(ss, &Segment{Text: segment.Token().Text(), Pos: segment.Token().Pos(),
+			Kind: segment.Token().Kind(), InDictionary: segment.InDictionary()})
 	}
 	response, _ := json.Marshal(&JsonResponse{Segments: ss})
 
@@ -78,7 +83,11 @@ func main() {
 	runtime.GOMAXPROCS(runtime.NumCPU())
 
 	// 初始化分词器
-	segmenter.LoadDictionary(*dict)
+	content, err := os.ReadFile(*dict)
+	if err != nil {
+		log.Fatal(err)
+	}
+	segmenter.LoadDictionary(string(content))
 
 	http.HandleFunc("/json", JsonRpcServer)
 	http.Handle("/", http.FileServer(http.Dir(*staticFolder)))
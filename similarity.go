@@ -0,0 +1,78 @@
+package sego
+
+import (
+	"math"
+)
+
+// 计算两个分词结果的Jaccard相似度，即两者词集合的交集大小除以并集大小
+//
+// 词按分词文本比较，重复出现的词只算一次。两者都为空时返回0。
+func JaccardSimilarity(a, b []Segment) float64 {
+	setA := UnigramFreq(a)
+	setB := UnigramFreq(b)
+	intersection := 0
+	for word := range setA {
+		if _, ok := setB[word]; ok {
+			intersection++
+		}
+	}
+	union := len(setA) + len(setB) - intersection
+	if union == 0 {
+		return 0
+	}
+	return float64(intersection) / float64(union)
+}
+
+// 计算两个分词结果的词频向量的余弦相似度
+//
+// 任何一方为空时返回0。
+func CosineSimilarity(a, b []Segment) float64 {
+	freqA := UnigramFreq(a)
+	freqB := UnigramFreq(b)
+	var dot, normA, normB float64
+	for word, countA := range freqA {
+		dot += float64(countA * freqB[word])
+		normA += float64(countA * countA)
+	}
+	for _, countB := range freqB {
+		normB += float64(countB * countB)
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
+
+// 替换一个分词的默认代价，等于一次插入加一次删除，因此实际上不会使用替换
+const DefaultSubstitutionCost = 2
+
+// 计算把分词结果a变为b所需的最少分词插入、删除和替换次数（替换的代价为
+// DefaultSubstitutionCost），分词按文本比较
+//
+// 这是用标准切分评价分词器质量的常用指标。
+func SegmentEditDistance(a, b []Segment) int {
+	return SegmentEditDistanceWithCost(a, b, DefaultSubstitutionCost)
+}
+
+// 和SegmentEditDistance相同，但替换一个分词的代价为substitutionCost
+//
+// substitutionCost为1时即标准的Levenshtein距离。
+func SegmentEditDistanceWithCost(a, b []Segment, substitutionCost int) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := substitutionCost
+			if a[i-1].token.Text() == b[j-1].token.Text() {
+				cost = 0
+			}
+			current[j] = minInt(minInt(previous[j]+1, current[j-1]+1), previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
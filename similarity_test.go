@@ -0,0 +1,45 @@
+package sego
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSimilarity(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n众多 10 a\n经济 10 n\n")
+	a := seg.Segment([]byte("中国人口众多"))
+	b := seg.Segment([]byte("中国经济中国人口"))
+
+	// 词集合{中国 人口 众多}和{中国 经济 人口}
+	expect(t, "0.500", fmt.Sprintf("%.3f", JaccardSimilarity(a, b)))
+	expect(t, "1.000", fmt.Sprintf("%.3f", JaccardSimilarity(a, a)))
+	expect(t, "0", JaccardSimilarity(nil, nil))
+
+	// 词频向量(1,1,1,0)和(2,1,0,1)
+	expect(t, "0.707", fmt.Sprintf("%.3f", CosineSimilarity(a, b)))
+	expect(t, "1.000", fmt.Sprintf("%.3f", CosineSimilarity(b, b)))
+	expect(t, "0", CosineSimilarity(a, nil))
+	expect(t, "0", CosineSimilarity(a, seg.Segment([]byte("经济"))))
+}
+
+func TestSegmentEditDistance(t *testing.T) {
+	var gold, seg Segmenter
+	gold.LoadDictionary("中华 10 nz\n人民 10 n\n共和国 10 ns\n")
+	seg.LoadDictionary("中华人民共和国 10 ns\n")
+	text := []byte("中华人民共和国万岁")
+	a := gold.Segment(text)
+	b := seg.Segment(text)
+
+	// 中华 人民 共和国 万 岁 -> 中华人民共和国 万 岁
+	expect(t, "4", SegmentEditDistance(a, b))
+	expect(t, "4", SegmentEditDistance(b, a))
+	expect(t, "3", SegmentEditDistanceWithCost(a, b, 1))
+	expect(t, "0", SegmentEditDistance(a, a))
+	expect(t, "5", SegmentEditDistance(a, nil))
+
+	// 中华 人民 共和国 -> 中华 人 们 共和国
+	c := gold.Segment([]byte("中华人们共和国"))
+	expect(t, "3", SegmentEditDistance(a[:3], c))
+	expect(t, "2", SegmentEditDistanceWithCost(a[:3], c, 1))
+}
@@ -0,0 +1,62 @@
+//go:build go1.21
+// +build go1.21
+
+package sego
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// 输出到slog.Logger的Logger
+//
+// 除了Logger的方法外还实现了结构化的Debug、Info和Error，分词器的日志（比如
+// 载入词典时的"tokens_loaded"分词数）以键值对的形式输出，而不是拼接在消息中。
+type SlogLogger struct {
+	logger *slog.Logger
+}
+
+// 创建一个输出到l的SlogLogger，l为nil时使用slog.Default()
+func NewSlogLogger(l *slog.Logger) *SlogLogger {
+	if l == nil {
+		l = slog.Default()
+	}
+	return &SlogLogger{logger: l}
+}
+
+func (l *SlogLogger) Debugf(format string, args ...interface{}) {
+	l.logger.Debug(fmt.Sprintf(format, args...))
+}
+
+func (l *SlogLogger) Infof(format string, args ...interface{}) {
+	l.logger.Info(fmt.Sprintf(format, args...))
+}
+
+func (l *SlogLogger) Errorf(format string, args ...interface{}) {
+	l.logger.Error(fmt.Sprintf(format, args...))
+}
+
+func (l *SlogLogger) Debug(msg string, args ...interface{}) {
+	l.logger.Debug(msg, args...)
+}
+
+func (l *SlogLogger) Info(msg string, args ...interface{}) {
+	l.logger.Info(msg, args...)
+}
+
+func (l *SlogLogger) Error(msg string, args ...interface{}) {
+	l.logger.Error(msg, args...)
+}
+
+// 设置分词器输出结构化日志使用的slog.Logger
+//
+// 相当于SetLogger(NewSlogLogger(l))。l为nil时恢复使用包的logger（见SetLogger）。
+func WithSlogLogger(l *slog.Logger) Option {
+	return func(seg *Segmenter) {
+		if l == nil {
+			seg.customLogger = nil
+			return
+		}
+		seg.customLogger = NewSlogLogger(l)
+	}
+}
@@ -0,0 +1,52 @@
+//go:build go1.21
+// +build go1.21
+
+package sego
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+)
+
+func TestWithSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	seg := NewSegmenter(WithSlogLogger(slog.New(handler)))
+	seg.LoadDictionary("中国 10 ns\n人口\n\n人口 10 n\n")
+	expect(t, "level=DEBUG msg=sego词典跳过一行 line=2 text=人口\n"+
+		"level=INFO msg=sego词典字符串载入完毕 tokens_loaded=2 lines_skipped=1\n", buf.String())
+
+	seg = NewSegmenter(WithSlogLogger(nil))
+	expect(t, "true", seg.customLogger == nil)
+}
+
+func TestSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	var l Logger = NewSlogLogger(slog.New(handler))
+	l.Infof("载入%d个分词", 2)
+	l.Debugf("不输出")
+	expect(t, "level=INFO msg=载入2个分词\n", buf.String())
+
+	buf.Reset()
+	var seg Segmenter
+	seg.SetLogger(l)
+	seg.LoadDictionary("中国 10 ns\n")
+	expect(t, "level=INFO msg=sego词典字符串载入完毕 tokens_loaded=1 lines_skipped=0\n", buf.String())
+}
@@ -0,0 +1,98 @@
+package sego
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+)
+
+// 摘要中不相邻的片段之间的连接符
+const snippetSeparator = "..."
+
+// 对文本分词，截取查询词匹配最密集的位置附近的contextWords个分词作为摘要
+//
+// 查询词的匹配规则和Highlight相同。先选出包含匹配分词最多的窗口，匹配数相同
+// 时选匹配分词最靠近窗口中间的，然后继续选择和已选窗口不重叠的包含匹配的窗口，
+// 直到没有这样的窗口。各窗口按在文本中的顺序用"..."连接，首尾的空白被去掉。
+// 没有匹配时返回文本开头的contextWords个分词，contextWords不大于0时返回空
+// 字符串。
+func Snippet(text []byte, queryTerms []string, seg *Segmenter, contextWords int) string {
+	if contextWords <= 0 {
+		return ""
+	}
+	terms := make([]string, 0, len(queryTerms))
+	for _, term := range queryTerms {
+		if term != "" {
+			terms = append(terms, strings.ToLower(term))
+		}
+	}
+
+	segments := seg.Segment(text)
+	if len(segments) == 0 {
+		return ""
+	}
+	size := contextWords
+	if size > len(segments) {
+		size = len(segments)
+	}
+
+	matched := make([]bool, len(segments))
+	for i, s := range segments {
+		matched[i] = highlightMatches(s.token.Text(), terms)
+	}
+
+	// chosen[i]表示第i个分词已经属于某个选中的窗口
+	chosen := make([]bool, len(segments))
+	var starts []int
+	for {
+		best, bestCount, bestOffset := -1, 0, 0
+		for start := 0; start+size <= len(segments); start++ {
+			count, first, last, overlap := 0, -1, -1, false
+			for i := start; i < start+size; i++ {
+				if chosen[i] {
+					overlap = true
+					break
+				}
+				if matched[i] {
+					if first < 0 {
+						first = i
+					}
+					last = i
+					count++
+				}
+			}
+			if overlap || count == 0 {
+				continue
+			}
+			// 匹配分词的中点到窗口中点的距离（乘以2避免小数）
+			offset := first + last - 2*start - size + 1
+			if offset < 0 {
+				offset = -offset
+			}
+			if count > bestCount || (count == bestCount && offset < bestOffset) {
+				best, bestCount, bestOffset = start, count, offset
+			}
+		}
+		if best < 0 {
+			break
+		}
+		for i := best; i < best+size; i++ {
+			chosen[i] = true
+		}
+		starts = append(starts, best)
+	}
+	if len(starts) == 0 {
+		starts = append(starts, 0)
+	}
+
+	// 按文本中的位置输出
+	sort.Ints(starts)
+	var builder strings.Builder
+	for i, start := range starts {
+		if i > 0 {
+			builder.WriteString(snippetSeparator)
+		}
+		builder.Write(bytes.TrimSpace(text[segments[start].start:segments[start+size-1].end]))
+	}
+	return builder.String()
+}
@@ -0,0 +1,20 @@
+package sego
+
+import (
+	"testing"
+)
+
+func TestSnippet(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n众多 10 a\n历史 10 n\n悠久 10 a\n文化 10 n\n灿烂 10 a\n经济 10 n\n发展 10 vn\n迅速 10 ad\n")
+	text := []byte("中国历史悠久，文化灿烂，人口众多，经济发展迅速，文化交流频繁")
+
+	// 匹配在窗口中间
+	expect(t, "灿烂，人口众多，", Snippet(text, []string{"人口"}, &seg, 5))
+	// 选择匹配最密集的窗口，其余匹配用...连接
+	expect(t, "悠久，文化灿烂...发展迅速，文化", Snippet(text, []string{"中国", "文化", "发展"}, &seg, 4))
+	expect(t, "中国历史悠久", Snippet(text, []string{"不存在"}, &seg, 3))
+	expect(t, "", Snippet(text, []string{"人口"}, &seg, 0))
+	expect(t, "中国", Snippet([]byte("中国"), []string{"中国"}, &seg, 10))
+	expect(t, "", Snippet(nil, []string{"中国"}, &seg, 10))
+}
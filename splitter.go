@@ -0,0 +1,50 @@
+package sego
+
+// 字元划分器，将文本划分成字元（分词的最小单位）
+//
+// 分词时输入文本和词典中的分词都先用划分器划分成字元，因此划分器决定了哪些
+// 字符总是作为一个整体（比如把某类符号保持在一起）。Split返回的字元依次拼接
+// 后的长度必须和输入相同，否则分词的位置会出错；英文字元应当转换为小写，和
+// 默认实现一样，以便不区分大小写地匹配词典。
+type CharSplitter interface {
+	Split(text []byte) []Text
+}
+
+// 将普通函数作为CharSplitter使用
+type CharSplitterFunc func(text []byte) []Text
+
+// 调用f(text)
+func (f CharSplitterFunc) Split(text []byte) []Text {
+	return f(text)
+}
+
+// 默认的字元划分器，将连续的拉丁字母和数字合成一个字元并转为小写，其他字符
+// 各自成为一个字元
+type DefaultCharSplitter struct{}
+
+// 见DefaultCharSplitter
+func (DefaultCharSplitter) Split(text []byte) []Text {
+	return splitTextToWords(text)
+}
+
+// 设置分词器使用的字元划分器，设置后WithBoundaryPolicy不再起作用
+//
+// 载入词典时词典中的分词也用同样的划分器划分，因此需要在载入词典之前设置。
+func WithCharSplitter(splitter CharSplitter) Option {
+	return func(seg *Segmenter) {
+		seg.charSplitter = splitter
+	}
+}
+
+// 将文本划分成字元并以字符串返回，即不查词典时分词看到的文本
+//
+// 中日韩文字每个字一个字元，连续的拉丁字母和数字合成一个字元并转为小写，和
+// DefaultCharSplitter相同。可以用来实现自己的匹配算法。
+func SplitToAtoms(bytes []byte) []string {
+	words := splitTextToWords(bytes)
+	output := make([]string, len(words))
+	for i, word := range words {
+		output[i] = string(word)
+	}
+	return output
+}
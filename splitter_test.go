@@ -0,0 +1,40 @@
+package sego
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCharSplitter(t *testing.T) {
+	expect(t, "中/国/ok/", bytesToString(DefaultCharSplitter{}.Split([]byte("中国OK"))))
+
+	// 把"+"和"#"并入前一个字元，使"C++"和"C#"成为一个字元
+	keepSymbols := CharSplitterFunc(func(text []byte) []Text {
+		var output []Text
+		start := 0
+		for _, word := range splitTextToWords(text) {
+			end := start + len(word)
+			if n := len(output); n > 0 && (string(word) == "+" || string(word) == "#") {
+				output[n-1] = toLower(text[end-len(output[n-1])-len(word) : end])
+			} else {
+				output = append(output, word)
+			}
+			start = end
+		}
+		return output
+	})
+	expect(t, "c++/和/c#/", bytesToString(keepSymbols.Split([]byte("C++和C#"))))
+
+	seg := NewSegmenter(WithCharSplitter(keepSymbols), WithBoundaryPolicy(DefaultBoundaryPolicy{}))
+	seg.LoadDictionary("c++ 10 nz\n语言 10 n\n")
+	segments := seg.Segment([]byte("C++语言和C#"))
+	expect(t, "c++/nz 语言/n 和/x c#/x ", SegmentsToString(segments, false))
+	expect(t, "9", segments[2].start)
+	expect(t, "12", segments[3].start)
+	expect(t, "14", segments[3].end)
+}
+
+func TestSplitToAtoms(t *testing.T) {
+	expect(t, "[中 国 github   2024 年 ， a]", fmt.Sprint(SplitToAtoms([]byte("中国GitHub 2024年，A"))))
+	expect(t, "[]", fmt.Sprint(SplitToAtoms(nil)))
+}
@@ -0,0 +1,45 @@
+package sego
+
+// 词干提取器，将分词文本归一为规范形式
+//
+// 对英文可以是提取词干（比如"running"到"run"），对中文可以是把同一个词的不同
+// 写法归一为一种。sego不提供具体实现，调用者可以包装任意的词干提取库。
+type Stemmer interface {
+	Stem(word string) string
+}
+
+// 设置分词后使用的词干提取器，为nil时不做处理
+//
+// 设置后Segment、InternalSegment以及基于它们的函数输出的每个分词的文本都经过
+// s.Stem处理，文本改变的分词使用新的Token，位置仍然对应原文本，Stem返回空
+// 字符串时保留原分词。词典中分词的子分词（Token.Segments）不受影响。该函数
+// 会清空句子缓存，不能和分词并发调用。
+func (seg *Segmenter) SetStemmer(s Stemmer) {
+	seg.stemmer = s
+	seg.cache.clear()
+}
+
+// 返回用s处理分词文本的过滤器，可以加入FilterChain
+func StemFilter(s Stemmer) TokenFilter {
+	stemmer := &Segmenter{stemmer: s}
+	return TokenFilterFunc(func(segs []Segment) []Segment {
+		output := make([]Segment, len(segs))
+		copy(output, segs)
+		return stemmer.stemSegments(output)
+	})
+}
+
+// 用分词器的词干提取器处理分词文本，结果直接写回segs
+func (seg *Segmenter) stemSegments(segs []Segment) []Segment {
+	for i := range segs {
+		token := segs[i].token
+		text := token.Text()
+		stem := seg.stemmer.Stem(text)
+		if stem == "" || stem == text {
+			continue
+		}
+		segs[i].token = &Token{text: seg.splitText([]byte(stem)), frequency: token.frequency,
+			distance: token.distance, pos: token.pos, posTags: token.posTags, kind: token.kind}
+	}
+	return segs
+}
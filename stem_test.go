@@ -0,0 +1,50 @@
+package sego
+
+import (
+	"strings"
+	"testing"
+)
+
+// 测试用的词干提取器：去掉英文的"ing"和"s"后缀，"電腦"归一为"电脑"
+type testStemmer struct{}
+
+func (testStemmer) Stem(word string) string {
+	if word == "電腦" {
+		return "电脑"
+	}
+	if strings.HasSuffix(word, "ing") && len(word) > 5 {
+		return strings.TrimSuffix(word, "ing")
+	}
+	if strings.HasSuffix(word, "s") && len(word) > 3 {
+		return strings.TrimSuffix(word, "s")
+	}
+	return word
+}
+
+func TestSetStemmer(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("电脑 10 n\n電腦 10 n\n的 10 uj\n")
+	text := []byte("Testing的電腦和computers")
+	expect(t, "testing/x 的/uj 電腦/n 和/x computers/x ", SegmentsToString(seg.Segment(text), false))
+
+	seg.SetStemmer(testStemmer{})
+	segments := seg.Segment(text)
+	expect(t, "test/x 的/uj 电脑/n 和/x computer/x ", SegmentsToString(segments, false))
+	expect(t, "10", segments[2].Start())
+	expect(t, "16", segments[2].End())
+	expect(t, "電腦", segments[2].String(text))
+	// 不改变词典中的分词
+	expect(t, "電腦/n ", tokensToString(seg.Dictionary().LookupPrefix("電腦")))
+
+	seg.SetStemmer(nil)
+	expect(t, "testing/x 的/uj 電腦/n 和/x computers/x ", SegmentsToString(seg.Segment(text), false))
+}
+
+func TestStemFilter(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("电脑 10 n\n電腦 10 n\n")
+	segments := seg.Segment([]byte("電腦computers"))
+	chain := NewFilterChain(StemFilter(testStemmer{}), DeduplicateFilter())
+	expect(t, "电脑/n computer/x ", SegmentsToString(chain.Apply(segments), false))
+	expect(t, "電腦/n computers/x ", SegmentsToString(segments, false))
+}
@@ -0,0 +1,100 @@
+package sego
+
+import (
+	"unicode/utf8"
+)
+
+// 对增量到达的文本（比如聊天输入）分词的有状态分词器
+//
+// 每次Append之后只输出已经稳定的前缀部分的分词，末尾可能和后续文本组成更长
+// 分词的字元（个数为词典中最长分词的字元数）暂不输出，Flush时输出剩余的部分。
+// 输出的分词位置是在整个输入流中的位置。
+//
+// 稳定前缀的划分假设末尾之外的分词不受后续文本影响，对绝大多数文本成立，但
+// 最短路径算法是全局的，个别情况下和一次性对全部文本分词的结果可能不同。
+// 使用改变文本长度的规范化选项或者保护模式（可能跨越很多字元）时不应使用。
+// StreamTokenizer不能被多个goroutine同时使用。
+type StreamTokenizer struct {
+	segmenter *Segmenter
+
+	// 尚未输出的文本
+	buffer []byte
+
+	// buffer开始处在输入流中的字节位置和字符位置
+	offset     int
+	runeOffset int
+}
+
+// 创建使用分词器seg的流式分词器，seg应已载入词典
+func NewStreamTokenizer(seg *Segmenter) *StreamTokenizer {
+	return &StreamTokenizer{segmenter: seg}
+}
+
+// 追加一段文本，返回可以确定的分词
+//
+// bytes可以在多字节字符的中间截断，不完整的字符会等待后续的文本。返回的分词
+// 按顺序排列，和之前返回的分词首尾相接。
+func (stream *StreamTokenizer) Append(bytes []byte) []Segment {
+	stream.buffer = append(stream.buffer, bytes...)
+
+	// 末尾不完整的UTF-8字符和最后若干个字元都暂不输出
+	complete := len(stream.buffer)
+	for i := len(stream.buffer) - 1; i >= 0 && i >= len(stream.buffer)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(stream.buffer[i]) {
+			if !utf8.FullRune(stream.buffer[i:]) {
+				complete = i
+			}
+			break
+		}
+	}
+	words := stream.segmenter.splitText(stream.buffer[:complete])
+	boundary := complete
+	for i := len(words) - 1; i >= 0 && i >= len(words)-stream.holdBack(); i-- {
+		boundary -= len(words[i])
+	}
+	if boundary <= 0 {
+		return []Segment{}
+	}
+	return stream.emit(stream.segmenter.Segment(stream.buffer[:complete]), boundary)
+}
+
+// 输出剩余文本的所有分词并清空状态，之后可以继续Append新的文本
+func (stream *StreamTokenizer) Flush() []Segment {
+	output := stream.emit(stream.segmenter.Segment(stream.buffer), len(stream.buffer))
+	stream.buffer = nil
+	return output
+}
+
+// 输出segments中结束位置不超过boundary的分词，并从buffer中去掉对应的文本
+func (stream *StreamTokenizer) emit(segments []Segment, boundary int) []Segment {
+	output := make([]Segment, 0, len(segments))
+	end, runeEnd := 0, 0
+	for _, s := range segments {
+		if s.end > boundary {
+			break
+		}
+		end, runeEnd = s.end, s.runeEnd
+		s.start += stream.offset
+		s.end += stream.offset
+		s.runeStart += stream.runeOffset
+		s.runeEnd += stream.runeOffset
+		output = append(output, s)
+	}
+	// 伪分词的文本指向buffer，剩余的文本需要复制到新的数组，不能原地移动
+	stream.buffer = append([]byte(nil), stream.buffer[end:]...)
+	stream.offset += end
+	stream.runeOffset += runeEnd
+	return output
+}
+
+// 返回暂不输出的末尾字元数，即分词可能包含的最多字元数
+func (stream *StreamTokenizer) holdBack() int {
+	hold := 1
+	if stream.segmenter.dict != nil {
+		hold = maxInt(hold, stream.segmenter.dict.maxTokenLength)
+	}
+	if stream.segmenter.maxTokenLength > 0 {
+		hold = minInt(hold, stream.segmenter.maxTokenLength)
+	}
+	return hold
+}
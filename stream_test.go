@@ -0,0 +1,63 @@
+package sego
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestStreamTokenizer(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中华 10 nz\n人民 10 n\n共和国 10 ns\n中华人民共和国 10 ns\n成立 10 v\n了 10 ul\ngithub 10 nz\n")
+	text := []byte("中华人民共和国成立了，GitHub上线了")
+	expected := SegmentsToString(seg.Segment(text), false)
+
+	// 逐字节追加，包括在多字节字符中间截断
+	stream := NewStreamTokenizer(&seg)
+	var output []Segment
+	for i := range text {
+		output = append(output, stream.Append(text[i:i+1])...)
+	}
+	// 最后若干个字元还没有输出
+	expect(t, "true", len(output) > 0 && output[len(output)-1].End() < len(text))
+	output = append(output, stream.Flush()...)
+	expect(t, expected, SegmentsToString(output, false))
+
+	// 位置首尾相接，是在整个输入流中的位置
+	position, runePosition := 0, 0
+	for _, s := range output {
+		expect(t, fmt.Sprint(position, runePosition), fmt.Sprint(s.Start(), s.RuneStart()))
+		expect(t, s.Token().Text(), string(toLower(s.Bytes(text))))
+		position, runePosition = s.End(), s.RuneEnd()
+	}
+	expect(t, fmt.Sprint(len(text)), position)
+
+	// 刚追加的可能组成长分词的部分暂不输出
+	stream = NewStreamTokenizer(&seg)
+	expect(t, "", SegmentsToString(stream.Append([]byte("中华人民")), false))
+	expect(t, "中华人民共和国/ns 成立/v 了/ul ",
+		SegmentsToString(stream.Append([]byte("共和国成立了，好的好的好的")), false))
+	expect(t, "，/w 好/x 的/x 好/x 的/x 好/x 的/x ", SegmentsToString(stream.Flush(), false))
+	expect(t, "0", len(stream.Flush()))
+
+	// Flush之后可以继续使用
+	segments := append(stream.Append([]byte("GitHub")), stream.Flush()...)
+	expect(t, "github/nz ", SegmentsToString(segments, false))
+	expect(t, "51", segments[0].Start())
+}
+
+func TestStreamTokenizerPseudoTokens(t *testing.T) {
+	// 词典中没有的字都是伪分词，分词文本指向流式分词器的缓冲区
+	var seg Segmenter
+	seg.LoadDictionary("中华人民 10 nz\n")
+	stream := NewStreamTokenizer(&seg)
+
+	var output []Segment
+	output = append(output, stream.Append([]byte("甲乙丙丁戊己庚"))...)
+	expect(t, "甲/x 乙/x 丙/x ", SegmentsToString(output, false))
+	output = append(output, stream.Append([]byte("辛壬"))...)
+	output = append(output, stream.Flush()...)
+	output = append(output, stream.Append([]byte("癸子丑寅卯"))...)
+	output = append(output, stream.Flush()...)
+	expect(t, "甲/x 乙/x 丙/x 丁/x 戊/x 己/x 庚/x 辛/x 壬/x 癸/x 子/x 丑/x 寅/x 卯/x ",
+		SegmentsToString(output, false))
+}
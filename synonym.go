@@ -0,0 +1,95 @@
+package sego
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+)
+
+// 从字符串中载入同义词表
+//
+// 同义词表的格式为（每组同义词一行，#开头的行为注释）：
+//
+//	词1 词2 词3 ...
+//
+// 一个词可以出现在多个组中。英文词不区分大小写。只有一个词的行是错误，这时
+// 返回错误且不改变已载入的同义词表。重复调用会替换之前载入的同义词表。
+func (dict *Dictionary) LoadSynonyms(content string) error {
+	synonyms := make(map[string][]string)
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		words := strings.Fields(scanner.Text())
+		if len(words) == 0 || strings.HasPrefix(words[0], "#") {
+			continue
+		}
+		if len(words) < 2 {
+			return fmt.Errorf("sego: 同义词表第%d行只有一个词", lineNumber)
+		}
+
+		for i, word := range words {
+			key := synonymKey(word)
+			for j, synonym := range words {
+				if i != j && !containsString(synonyms[key], synonym) && synonymKey(synonym) != key {
+					synonyms[key] = append(synonyms[key], synonym)
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	dict.synonyms = synonyms
+	return nil
+}
+
+// 返回word的所有同义词（不包括word本身），按同义词表中出现的顺序排列
+//
+// 没有同义词时返回nil。
+func (dict *Dictionary) Synonyms(word string) []string {
+	return dict.synonyms[synonymKey(word)]
+}
+
+// 同义词表中词的键，和词典一样英文词不区分大小写
+func synonymKey(word string) string {
+	return string(textSliceToBytes(splitTextToWords([]byte(word))))
+}
+
+func containsString(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
+
+// 对分词结果做同义词扩展，用于搜索时的查询扩展
+//
+// 输出和segs一一对应，每项的第一个元素是原分词，之后是每个同义词对应的分词。
+// 同义词分词的位置和原分词相同；同义词在词典中时使用词典中的分词，否则使用
+// 一个词性和原分词相同的新分词，其InDictionary按同义词本身是否在词典中设置。
+func (seg *Segmenter) ExpandSynonyms(segs []Segment) [][]Segment {
+	output := make([][]Segment, len(segs))
+	for i, s := range segs {
+		output[i] = []Segment{s}
+		if seg.dict == nil {
+			continue
+		}
+		for _, synonym := range seg.dict.Synonyms(s.token.Text()) {
+			alternative := s
+			alternative.token = seg.dict.tokenOrNew(synonym, s.token.pos)
+			alternative.inDictionary = alternative.token.dict != nil
+			output[i] = append(output[i], alternative)
+		}
+	}
+	return output
+}
+
+// 返回词典中文本为text的分词，词典中没有时返回一个词性为pos的新分词
+func (dict *Dictionary) tokenOrNew(text string, pos string) *Token {
+	words := splitTextToWords([]byte(text))
+	if value, err := dict.trie.Get(textSliceToBytes(words)); err == nil {
+		return dict.token(value)
+	}
+	return &Token{text: words, pos: pos, kind: KindPseudo}
+}
@@ -0,0 +1,36 @@
+package sego
+
+import (
+	"testing"
+)
+
+func TestSynonyms(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("电脑 10 n\n计算机 10 n\n便宜 10 a\n价格 10 n\n")
+	dict := seg.Dictionary()
+
+	expect(t, "sego: 同义词表第2行只有一个词", dict.LoadSynonyms("电脑 计算机\n便宜\n"))
+	expect(t, "[]", dict.Synonyms("电脑"))
+
+	expect(t, "<nil>", dict.LoadSynonyms("# 同义词\n电脑 计算机 PC\n\n便宜 实惠\n电脑 微机\n"))
+	expect(t, "[计算机 PC 微机]", dict.Synonyms("电脑"))
+	expect(t, "[电脑 计算机]", dict.Synonyms("pc"))
+	expect(t, "[便宜]", dict.Synonyms("实惠"))
+	expect(t, "[]", dict.Synonyms("价格"))
+
+	expanded := seg.ExpandSynonyms(seg.Segment([]byte("电脑价格便宜")))
+	expect(t, "3", len(expanded))
+	expect(t, "电脑/n 计算机/n pc/n 微机/n ", SegmentsToString(expanded[0], false))
+	expect(t, "价格/n ", SegmentsToString(expanded[1], false))
+	expect(t, "便宜/a 实惠/a ", SegmentsToString(expanded[2], false))
+	expect(t, "12", expanded[2][1].start)
+	expect(t, "18", expanded[2][1].end)
+
+	// InDictionary取决于同义词本身是否在词典中
+	expect(t, "true", expanded[0][1].InDictionary())
+	expect(t, "false", expanded[0][2].InDictionary())
+	expanded = seg.ExpandSynonyms(seg.Segment([]byte("PC")))
+	expect(t, "pc/x 电脑/n 计算机/n ", SegmentsToString(expanded[0], false))
+	expect(t, "false", expanded[0][0].InDictionary())
+	expect(t, "true", expanded[0][1].InDictionary())
+}
@@ -0,0 +1,61 @@
+package sego
+
+import (
+	"unicode/utf8"
+)
+
+// 分词结果的词汇统计，见AnalyzeText
+type TextStats struct {
+	// 分词总数，不包括空白和标点
+	TotalTokens int
+
+	// 不同的词数，词按分词文本比较
+	UniqueTokens int
+
+	// 类符形符比，即UniqueTokens/TotalTokens，越大说明用词越丰富
+	TypeTokenRatio float64
+
+	// 分词的平均字符数
+	AverageTokenLength float64
+
+	// 只出现一次的词数
+	HapaxLegomenaCount int
+
+	// 不在词典中的分词数（见Segment.InDictionary），包括伪分词和合并得到的分词
+	OOVCount int
+}
+
+// 统计分词结果的词汇丰富程度
+//
+// 空白和标点不算作词（见isCollocationWord），不参与任何一项统计。segs中没有
+// 其他分词时返回零值。
+func AnalyzeText(segs []Segment) TextStats {
+	var stats TextStats
+	freq, total := termFreq(segs)
+	if total == 0 {
+		return stats
+	}
+
+	totalLength := 0
+	for i := range segs {
+		text := segs[i].token.Text()
+		if !isCollocationWord(text) {
+			continue
+		}
+		totalLength += utf8.RuneCountInString(text)
+		if !segs[i].inDictionary {
+			stats.OOVCount++
+		}
+	}
+	for _, count := range freq {
+		if count == 1 {
+			stats.HapaxLegomenaCount++
+		}
+	}
+
+	stats.TotalTokens = total
+	stats.UniqueTokens = len(freq)
+	stats.TypeTokenRatio = float64(stats.UniqueTokens) / float64(stats.TotalTokens)
+	stats.AverageTokenLength = float64(totalLength) / float64(stats.TotalTokens)
+	return stats
+}
@@ -0,0 +1,24 @@
+package sego
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAnalyzeText(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n中华人民共和国 10 ns\n")
+
+	// 空白和标点不参与统计
+	stats := AnalyzeText(seg.Segment([]byte("中国人口，中国 人口多。中华人民共和国！")))
+	expect(t, "6", stats.TotalTokens)
+	expect(t, "4", stats.UniqueTokens)
+	expect(t, "0.6666666666666666", stats.TypeTokenRatio)
+	expect(t, "2.6666666666666665", stats.AverageTokenLength)
+	expect(t, "2", stats.HapaxLegomenaCount)
+	expect(t, "1", stats.OOVCount)
+	expect(t, fmt.Sprint(stats), AnalyzeText(seg.Segment([]byte("中国人口中国人口多中华人民共和国"))))
+
+	expect(t, "{0 0 0 0 0 0}", AnalyzeText(nil))
+	expect(t, "{0 0 0 0 0 0}", AnalyzeText(seg.Segment([]byte("， 。"))))
+}
@@ -0,0 +1,89 @@
+package sego
+
+import (
+	"errors"
+	"math"
+)
+
+// TF-IDF向量化器，用分词器对文档分词后计算每个词的TF-IDF值
+//
+// IDF使用平滑的形式log((1+N)/(1+df))+1，其中N为语料库中的文档数，df为包含该词的
+// 文档数，语料库中没有出现过的词df为零。TF为词在文档中出现的次数除以文档的
+// 总词数。空白和标点不算作词，既不计入文档频率也不计入总词数。
+type TFIDFVectorizer struct {
+	seg          *Segmenter
+	numDocuments int
+	docFreq      map[string]int
+}
+
+// 创建一个用seg分词的TF-IDF向量化器
+func NewTFIDFVectorizer(seg *Segmenter) *TFIDFVectorizer {
+	return &TFIDFVectorizer{seg: seg, docFreq: make(map[string]int)}
+}
+
+// 从语料库统计每个词的文档频率，替换之前统计的结果
+//
+// 语料库为空时返回错误。
+func (v *TFIDFVectorizer) Fit(corpus [][]byte) error {
+	if len(corpus) == 0 {
+		return errors.New("sego: TF-IDF语料库为空")
+	}
+	docFreq := make(map[string]int)
+	for _, segments := range v.seg.SegmentBatch(corpus, 0) {
+		freq, _ := termFreq(segments)
+		for word := range freq {
+			docFreq[word]++
+		}
+	}
+	v.numDocuments = len(corpus)
+	v.docFreq = docFreq
+	return nil
+}
+
+// 返回文档中每个词的TF-IDF值
+//
+// 没有调用Fit时所有词的IDF都是1。
+func (v *TFIDFVectorizer) Transform(doc []byte) map[string]float64 {
+	return v.transform(v.seg.Segment(doc))
+}
+
+// 先用corpus调用Fit，再返回corpus中每篇文档的TF-IDF值
+func (v *TFIDFVectorizer) FitTransform(corpus [][]byte) ([]map[string]float64, error) {
+	if err := v.Fit(corpus); err != nil {
+		return nil, err
+	}
+	output := make([]map[string]float64, len(corpus))
+	for i, segments := range v.seg.SegmentBatch(corpus, 0) {
+		output[i] = v.transform(segments)
+	}
+	return output, nil
+}
+
+// 返回词的IDF值
+func (v *TFIDFVectorizer) IDF(word string) float64 {
+	return math.Log(float64(1+v.numDocuments)/float64(1+v.docFreq[word])) + 1
+}
+
+func (v *TFIDFVectorizer) transform(segs []Segment) map[string]float64 {
+	scores := make(map[string]float64)
+	freq, total := termFreq(segs)
+	for word, count := range freq {
+		scores[word] = float64(count) / float64(total) * v.IDF(word)
+	}
+	return scores
+}
+
+// 统计分词结果中每个词出现的次数以及总词数，空白和标点不计（见isCollocationWord）
+func termFreq(segs []Segment) (map[string]int, int) {
+	freq := make(map[string]int)
+	total := 0
+	for _, s := range segs {
+		word := s.token.Text()
+		if !isCollocationWord(word) {
+			continue
+		}
+		freq[word]++
+		total++
+	}
+	return freq, total
+}
@@ -0,0 +1,42 @@
+package sego
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestTFIDFVectorizer(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人口 10 n\n经济 10 n\n发展 10 v\n")
+	v := NewTFIDFVectorizer(&seg)
+
+	// 没有调用Fit时IDF为1
+	expect(t, "map[中国:0.5 人口:0.5]", v.Transform([]byte("中国人口")))
+
+	expect(t, "sego: TF-IDF语料库为空", v.Fit(nil))
+	scores, err := v.FitTransform([][]byte{
+		[]byte("中国人口"),
+		[]byte("中国经济发展"),
+	})
+	expect(t, "<nil>", err)
+	expect(t, "2", len(scores))
+	expect(t, "1.000", fmt.Sprintf("%.3f", v.IDF("中国")))
+	expect(t, "1.405", fmt.Sprintf("%.3f", v.IDF("人口")))
+	expect(t, "2.099", fmt.Sprintf("%.3f", v.IDF("日本")))
+	expect(t, "0.500", fmt.Sprintf("%.3f", scores[0]["中国"]))
+	expect(t, "0.703", fmt.Sprintf("%.3f", scores[0]["人口"]))
+	expect(t, "0.468", fmt.Sprintf("%.3f", scores[1]["经济"]))
+
+	// 人口在这篇文档中出现两次
+	doc := v.Transform([]byte("人口人口中国"))
+	expect(t, "true", math.Abs(doc["人口"]-2.0/3*v.IDF("人口")) < 1e-9)
+
+	// 空白和标点不算作词
+	doc = v.Transform([]byte("人口， 人口。中国！"))
+	expect(t, "2", len(doc))
+	expect(t, "true", math.Abs(doc["人口"]-2.0/3*v.IDF("人口")) < 1e-9)
+	_, err = v.FitTransform([][]byte{[]byte("中国，人口。"), []byte("经济 发展！")})
+	expect(t, "<nil>", err)
+	expect(t, "2.099", fmt.Sprintf("%.3f", v.IDF("，")))
+}
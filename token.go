@@ -1,5 +1,7 @@
 package sego
 
+import "sync/atomic"
+
 // 字串类型，可以用来表达
 //	1. 一个字元，比如"中"又如"国", 英文的一个字元是一个词
 //	2. 一个分词，比如"中国"又如"人口"
@@ -19,11 +21,26 @@ type Token struct {
 	// sum(distance(分词))的最小值，这就是“最短路径”的来历。
 	distance float32
 
-	// 词性标注
+	// 词性标注，有多个词性时为权重最大的词性
 	pos string
 
+	// 词典中给出了多个词性或者词性权重时的全部词性，否则为nil，见POSTags
+	posTags []POSWeight
+
 	// 该分词文本的进一步分词划分，见Segments函数注释。
 	segments []*Segment
+
+	// 非零表示segments已经构建，见Dictionary.buildSegments
+	segmentsBuilt uint32
+
+	// 分词所在的词典，用于延迟构建segments，伪分词为nil
+	dict *Dictionary
+
+	// 是否为强制分词，见Segmenter.ForceWord
+	forced bool
+
+	// 分词的来源类别，词典中的分词为零值KindDictionary
+	kind Kind
 }
 
 // 返回分词文本
@@ -41,11 +58,21 @@ func (token *Token) Pos() string {
 	return token.pos
 }
 
+// 返回分词的来源类别，比如词典中的分词或者词典中没有的伪分词
+func (token *Token) Kind() Kind {
+	return token.kind
+}
+
 // 该分词文本的进一步分词划分，比如"中华人民共和国中央人民政府"这个分词
 // 有两个子分词"中华人民共和国"和"中央人民政府"。子分词也可以进一步有子分词
 // 形成一个树结构，遍历这个树就可以得到该分词的所有细致分词划分，这主要
 // 用于搜索引擎对一段文本进行全文搜索。
+//
+// 子分词在第一次用到时构建（除非使用了WithEagerSubSegments），可以并发调用。
 func (token *Token) Segments() []*Segment {
+	if token.dict != nil && atomic.LoadUint32(&token.segmentsBuilt) == 0 {
+		token.dict.buildSegments(token)
+	}
 	return token.segments
 }
 
@@ -0,0 +1,62 @@
+package sego
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SegmentUnique的过滤选项
+type UniqueOption int
+
+const (
+	// 跳过只有一个字符的分词
+	UniqueSkipSingleChar UniqueOption = iota + 1
+
+	// 跳过全部由标点和符号组成的分词
+	UniqueSkipPunct
+)
+
+// 对文本分词，返回其中不重复的分词文本
+//
+// 每个分词文本只出现一次，按在文本中第一次出现的顺序排列，全部由空白字符组成
+// 的分词被跳过。options可以进一步跳过单字符分词和标点分词，适合用来构建词表。
+func (seg *Segmenter) SegmentUnique(bytes []byte, options ...UniqueOption) []string {
+	skipSingleChar, skipPunct := false, false
+	for _, option := range options {
+		switch option {
+		case UniqueSkipSingleChar:
+			skipSingleChar = true
+		case UniqueSkipPunct:
+			skipPunct = true
+		}
+	}
+
+	output := []string{}
+	seen := make(map[string]bool)
+	for _, s := range seg.internalSegment(bytes, false) {
+		text := s.token.Text()
+		if seen[text] || strings.TrimSpace(text) == "" {
+			continue
+		}
+		if skipSingleChar && utf8.RuneCountInString(text) == 1 {
+			continue
+		}
+		if skipPunct && isPunctText(text) {
+			continue
+		}
+		seen[text] = true
+		output = append(output, text)
+	}
+	return output
+}
+
+// 判断文本是否全部由标点和符号组成
+func isPunctText(text string) bool {
+	for _, r := range text {
+		if !unicode.IsPunct(r) && !unicode.IsSymbol(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,18 @@
+package sego
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSegmentUnique(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary("中国 10 ns\n人民 10 n\n的 10 uj\n")
+
+	text := []byte("中国的人民，中国的 人民！……")
+	expect(t, "[中国 的 人民 ， ！ …]", fmt.Sprint(seg.SegmentUnique(text)))
+	expect(t, "[中国 的 人民]", fmt.Sprint(seg.SegmentUnique(text, UniqueSkipPunct)))
+	expect(t, "[中国 人民]", fmt.Sprint(seg.SegmentUnique(text, UniqueSkipSingleChar)))
+	expect(t, "[中国 人民]", fmt.Sprint(seg.SegmentUnique(text, UniqueSkipSingleChar, UniqueSkipPunct)))
+	expect(t, "[]", fmt.Sprint(seg.SegmentUnique([]byte(" \n"))))
+}
@@ -3,6 +3,7 @@ package sego
 import (
 	"bytes"
 	"fmt"
+	"unicode/utf8"
 )
 
 // 输出分词结果为字符串
@@ -30,14 +31,14 @@ func SegmentsToString(segs []Segment, searchMode bool) (output string) {
 
 func tokenToString(token *Token) (output string) {
 	hasOnlyTerminalToken := true
-	for _, s := range token.segments {
-		if len(s.token.segments) > 1 {
+	for _, s := range token.Segments() {
+		if len(s.token.Segments()) > 1 {
 			hasOnlyTerminalToken = false
 		}
 	}
 
 	if !hasOnlyTerminalToken {
-		for _, s := range token.segments {
+		for _, s := range token.Segments() {
 			if s != nil {
 				output += tokenToString(s.token)
 			}
@@ -72,13 +73,13 @@ func SegmentsToSlice(segs []Segment, searchMode bool) (output []string) {
 
 func tokenToSlice(token *Token) (output []string) {
 	hasOnlyTerminalToken := true
-	for _, s := range token.segments {
-		if len(s.token.segments) > 1 {
+	for _, s := range token.Segments() {
+		if len(s.token.Segments()) > 1 {
 			hasOnlyTerminalToken = false
 		}
 	}
 	if !hasOnlyTerminalToken {
-		for _, s := range token.segments {
+		for _, s := range token.Segments() {
 			output = append(output, tokenToSlice(s.token)...)
 		}
 	}
@@ -127,6 +128,28 @@ func textSliceByteLength(text []Text) (length int) {
 	return
 }
 
+// 返回多个字元的字符（Unicode码点）总数，非法UTF8字节每个计为一个字符
+func textSliceRuneCount(text []Text) (count int) {
+	for _, word := range text {
+		count += utf8.RuneCount(word)
+	}
+	return
+}
+
+// 将文本中的字节位置转换为字符（Unicode码点）位置
+//
+// 非法UTF8字节每个计为一个字符，和Segment.RuneStart/RuneEnd的计算方式一致。
+// byteOff超出文本范围时按文本边界处理。
+func ByteOffsetToRuneOffset(text []byte, byteOff int) int {
+	if byteOff <= 0 {
+		return 0
+	}
+	if byteOff > len(text) {
+		byteOff = len(text)
+	}
+	return utf8.RuneCount(text[:byteOff])
+}
+
 func textSliceToBytes(text []Text) []byte {
 	var buf bytes.Buffer
 	for _, word := range text {
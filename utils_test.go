@@ -142,3 +142,15 @@ func Test_Token_Split(t *testing.T) {
 		}
 	}
 }
+
+func Test_ByteOffsetToRuneOffset(t *testing.T) {
+	text := []byte("ab中国\xe4c")
+	assert.Equal(t, ByteOffsetToRuneOffset(text, 0), 0)
+	assert.Equal(t, ByteOffsetToRuneOffset(text, 2), 2)
+	assert.Equal(t, ByteOffsetToRuneOffset(text, 5), 3)
+	assert.Equal(t, ByteOffsetToRuneOffset(text, 8), 4)
+	assert.Equal(t, ByteOffsetToRuneOffset(text, 9), 5)
+	assert.Equal(t, ByteOffsetToRuneOffset(text, 10), 6)
+	assert.Equal(t, ByteOffsetToRuneOffset(text, 100), 6)
+	assert.Equal(t, ByteOffsetToRuneOffset(text, -1), 0)
+}
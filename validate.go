@@ -0,0 +1,93 @@
+package sego
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// 词典检查发现的问题类型
+type DictIssueKind int
+
+const (
+	dictLineOK    DictIssueKind = iota // 正常的分词行，不是问题
+	dictLineBlank                      // 空行或注释行，不是问题
+
+	// 格式错误的行：字段数不足两个或频率不是整数
+	DictIssueMalformed
+
+	// 频率低于载入阈值的分词，载入时会被忽略
+	DictIssueLowFrequency
+
+	// 和前面某行重复的分词，载入时以第一次出现的为准
+	DictIssueDuplicate
+
+	// 和前面某行重复且词性不同的分词
+	DictIssuePOSConflict
+)
+
+// 词典中的一个问题
+type DictIssue struct {
+	Line   int           // 问题所在的行号，从1开始
+	Kind   DictIssueKind // 问题类型
+	Text   string        // 分词文本，格式错误时为整行内容
+	Reason string        // 问题描述
+}
+
+// 检查词典内容，返回发现的问题而不载入词典
+//
+// 词典按照和Segmenter.LoadDictionary相同的规则解析，可以在合并多个词典后、
+// 载入之前用来发现格式错误、低频分词、重复分词和词性冲突。英文分词按小写
+// 比较，因此"GitHub"和"github"被认为是重复的。
+func ValidateDictionary(content string) ([]DictIssue, error) {
+	issues := []DictIssue{}
+
+	// 记录每个分词第一次出现的行号和词性
+	type firstSeen struct {
+		line int
+		pos  string
+	}
+	seen := make(map[string]firstSeen)
+
+	reader := bufio.NewReader(strings.NewReader(strings.TrimPrefix(content, utf8BOM)))
+	for lineNumber := 1; ; lineNumber++ {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return issues, err
+		}
+		if err != nil && len(line) == 0 {
+			break
+		}
+
+		entry, kind := parseDictionaryLine(line)
+		switch kind {
+		case dictLineBlank:
+			continue
+		case DictIssueMalformed:
+			issues = append(issues, DictIssue{Line: lineNumber, Kind: kind,
+				Text: strings.TrimSpace(line), Reason: "格式错误，应为\"分词文本 频率 词性\""})
+			continue
+		case DictIssueLowFrequency:
+			issues = append(issues, DictIssue{Line: lineNumber, Kind: kind, Text: entry.text,
+				Reason: fmt.Sprintf("频率%d低于载入阈值%d", entry.frequency, minTokenFrequency)})
+			continue
+		}
+
+		key := string(textSliceToBytes(splitTextToWords([]byte(entry.text))))
+		first, ok := seen[key]
+		if !ok {
+			seen[key] = firstSeen{line: lineNumber, pos: entry.pos}
+			continue
+		}
+		if first.pos != entry.pos {
+			issues = append(issues, DictIssue{Line: lineNumber, Kind: DictIssuePOSConflict,
+				Text: entry.text, Reason: fmt.Sprintf("与第%d行重复，词性\"%s\"和\"%s\"冲突",
+					first.line, entry.pos, first.pos)})
+		} else {
+			issues = append(issues, DictIssue{Line: lineNumber, Kind: DictIssueDuplicate,
+				Text: entry.text, Reason: fmt.Sprintf("与第%d行重复", first.line)})
+		}
+	}
+	return issues, nil
+}
@@ -0,0 +1,33 @@
+package sego
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestValidateDictionary(t *testing.T) {
+	content := "中国 10 ns\n" +
+		"\n" +
+		"人口\n" +
+		"人口 abc n\n" +
+		"十三亿 1 m\n" +
+		"中国 20 ns\n" +
+		"GitHub 5 nz\n" +
+		"github 5 n\n" +
+		"人口 10 n"
+	issues, err := ValidateDictionary(content)
+	expect(t, "<nil>", err)
+
+	output := ""
+	for _, issue := range issues {
+		output += fmt.Sprintf("%d:%d:%s ", issue.Line, issue.Kind, issue.Text)
+	}
+	expect(t, fmt.Sprintf("3:%d:人口 4:%d:人口 abc n 5:%d:十三亿 6:%d:中国 8:%d:github ",
+		DictIssueMalformed, DictIssueMalformed, DictIssueLowFrequency,
+		DictIssueDuplicate, DictIssuePOSConflict), output)
+	expect(t, "与第7行重复，词性\"n\"和\"nz\"冲突", issues[4].Reason)
+
+	issues, err = ValidateDictionary("\uFEFF中国 10 ns\n# 注释 abc\n  # 人口\n人口 10 n\n")
+	expect(t, "<nil>", err)
+	expect(t, "0", len(issues))
+}
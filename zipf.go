@@ -0,0 +1,52 @@
+package sego
+
+import (
+	"math"
+	"sort"
+)
+
+// 用齐普夫定律拟合词典的词频分布，返回指数和拟合优度R²
+//
+// 词频从大到小排列后，对log(排名)和log(词频)做最小二乘线性回归，指数为回归
+// 直线斜率的相反数。自然语言的指数通常接近1，明显偏离（比如远小于1）可能说明
+// 词典偏重某个领域或者不完整。R²越接近1说明词频越符合幂律分布。词频不大于零的
+// 分词不参与拟合；参与拟合的分词少于两个时返回两个零，所有词频都相同时指数为
+// 零，R²为1。
+func FitZipf(dict *Dictionary) (exponent float64, r2 float64) {
+	if dict == nil {
+		return 0, 0
+	}
+	frequencies := make([]int, 0, len(dict.tokens))
+	for i := range dict.tokens {
+		if frequency := dict.token(i).frequency; frequency > 0 {
+			frequencies = append(frequencies, frequency)
+		}
+	}
+	if len(frequencies) < 2 {
+		return 0, 0
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(frequencies)))
+
+	// x为log(排名)，y为log(词频)
+	n := float64(len(frequencies))
+	var sumX, sumY float64
+	for i, frequency := range frequencies {
+		sumX += math.Log(float64(i + 1))
+		sumY += math.Log(float64(frequency))
+	}
+	meanX, meanY := sumX/n, sumY/n
+	var sxx, sxy, syy float64
+	for i, frequency := range frequencies {
+		dx := math.Log(float64(i+1)) - meanX
+		dy := math.Log(float64(frequency)) - meanY
+		sxx += dx * dx
+		sxy += dx * dy
+		syy += dy * dy
+	}
+
+	slope := sxy / sxx
+	if syy == 0 {
+		return 0, 1
+	}
+	return -slope, sxy * sxy / (sxx * syy)
+}
@@ -0,0 +1,31 @@
+package sego
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFitZipf(t *testing.T) {
+	// 词频和排名严格成反比
+	var seg Segmenter
+	seg.LoadDictionary("一 1200 m\n二 600 m\n三 400 m\n四 300 m\n五 240 m\n六 200 m\n")
+	exponent, r2 := FitZipf(seg.Dictionary())
+	expect(t, "1.0000 1.0000", fmt.Sprintf("%.4f %.4f", exponent, r2))
+
+	var skewed Segmenter
+	skewed.LoadDictionary("中国 100 ns\n人口 80 n\n经济 10 n\n发展 5 v\n国家 40 n\n日本 2 ns\n")
+	exponent, r2 = FitZipf(skewed.Dictionary())
+	expect(t, "2.2024 0.8440", fmt.Sprintf("%.4f %.4f", exponent, r2))
+
+	var flat Segmenter
+	flat.LoadDictionary("中国 10 ns\n人口 10 n\n")
+	exponent, r2 = FitZipf(flat.Dictionary())
+	expect(t, "0 1", fmt.Sprint(exponent, r2))
+
+	var single Segmenter
+	single.LoadDictionary("中国 10 ns\n")
+	exponent, r2 = FitZipf(single.Dictionary())
+	expect(t, "0 0", fmt.Sprint(exponent, r2))
+	exponent, r2 = FitZipf(nil)
+	expect(t, "0 0", fmt.Sprint(exponent, r2))
+}